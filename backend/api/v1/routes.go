@@ -3,22 +3,194 @@ package v1
 import "github.com/gin-gonic/gin"
 
 // RegisterClassRoutes registers class-related endpoints for the API.
+// The class list requires a teacher; class details, QR code and live feed require the owning teacher.
+// Joining and leaving stay open to students.
 func RegisterClassRoutes(
 	rg *gin.RouterGroup,
+	requireTeacher gin.HandlerFunc,
+	requireClassOwner gin.HandlerFunc,
 	getClasses gin.HandlerFunc,
 	getClass gin.HandlerFunc,
 	getClassQRCode gin.HandlerFunc,
 	handleStudentJoin gin.HandlerFunc,
+	submitStudentJoin gin.HandlerFunc,
+	leaveClass gin.HandlerFunc,
 	handleWebSocket gin.HandlerFunc,
 ) {
-	rg.GET("/classes", getClasses)
-	rg.GET("/classes/:classId", getClass)
-	rg.GET("/classes/:classId/qr", getClassQRCode)
+	rg.GET("/classes", requireTeacher, getClasses)
+	rg.GET("/classes/:classId", requireClassOwner, getClass)
+	rg.GET("/classes/:classId/qr", requireClassOwner, getClassQRCode)
 	rg.GET("/classes/:classId/join", handleStudentJoin)
-	rg.GET("/classes/:classId/ws", handleWebSocket)
+	rg.POST("/classes/:classId/join", submitStudentJoin)
+	rg.POST("/classes/:classId/leave", leaveClass)
+	rg.GET("/classes/:classId/ws", requireClassOwner, handleWebSocket)
+}
+
+// RegisterClassManagementRoutes registers class create, update, archive and delete endpoints for the API.
+// Any teacher may create a class; only its owner may change it.
+func RegisterClassManagementRoutes(
+	rg *gin.RouterGroup,
+	requireTeacher gin.HandlerFunc,
+	requireClassOwner gin.HandlerFunc,
+	createClass gin.HandlerFunc,
+	updateClass gin.HandlerFunc,
+	archiveClass gin.HandlerFunc,
+	deleteClass gin.HandlerFunc,
+) {
+	rg.POST("/classes", requireTeacher, createClass)
+	rg.PATCH("/classes/:classId", requireClassOwner, updateClass)
+	rg.POST("/classes/:classId/archive", requireClassOwner, archiveClass)
+	rg.DELETE("/classes/:classId", requireClassOwner, deleteClass)
+}
+
+// RegisterStudentRoutes registers student record endpoints for the API.
+// Any teacher may add a student; renaming and deleting are limited to the teacher owning
+// every class the student is enrolled in.
+func RegisterStudentRoutes(
+	rg *gin.RouterGroup,
+	requireTeacher gin.HandlerFunc,
+	requireStudentOwner gin.HandlerFunc,
+	createStudent gin.HandlerFunc,
+	renameStudent gin.HandlerFunc,
+	deleteStudent gin.HandlerFunc,
+) {
+	rg.POST("/students", requireTeacher, createStudent)
+	rg.PATCH("/students/:studentId", requireStudentOwner, renameStudent)
+	rg.DELETE("/students/:studentId", requireStudentOwner, deleteStudent)
+}
+
+// RegisterRosterRoutes registers class enrollment, guest promotion and roster import endpoints for the API.
+func RegisterRosterRoutes(
+	rg *gin.RouterGroup,
+	getClassStudents gin.HandlerFunc,
+	enrollStudent gin.HandlerFunc,
+	unenrollStudent gin.HandlerFunc,
+	importClassStudents gin.HandlerFunc,
+	promoteGuest gin.HandlerFunc,
+) {
+	rg.GET("/classes/:classId/students", getClassStudents)
+	rg.POST("/classes/:classId/students", enrollStudent)
+	rg.DELETE("/classes/:classId/students/:studentId", unenrollStudent)
+	rg.POST("/classes/:classId/students/import", importClassStudents)
+	rg.POST("/classes/:classId/students/promote", promoteGuest)
+}
+
+// RegisterAttendanceRoutes registers attendance reporting, removal and waiting list endpoints for the API.
+func RegisterAttendanceRoutes(
+	rg *gin.RouterGroup,
+	getClassAttendance gin.HandlerFunc,
+	removeFromAttendance gin.HandlerFunc,
+	getClassWaitlist gin.HandlerFunc,
+) {
+	rg.GET("/classes/:classId/attendance", getClassAttendance)
+	rg.POST("/classes/:classId/attendance/remove", removeFromAttendance)
+	rg.GET("/classes/:classId/waitlist", getClassWaitlist)
+}
+
+// RegisterClassStateRoutes registers the live class state endpoint for the API.
+func RegisterClassStateRoutes(rg *gin.RouterGroup, getClassState gin.HandlerFunc) {
+	rg.GET("/classes/:classId/state", getClassState)
+}
+
+// RegisterPointRoutes registers point ledger endpoints for the API.
+func RegisterPointRoutes(
+	rg *gin.RouterGroup,
+	getClassPoints gin.HandlerFunc,
+	awardPoints gin.HandlerFunc,
+	deductPoints gin.HandlerFunc,
+) {
+	rg.GET("/classes/:classId/points", getClassPoints)
+	rg.POST("/classes/:classId/points/award", awardPoints)
+	rg.POST("/classes/:classId/points/deduct", deductPoints)
+}
+
+// RegisterSessionRoutes registers class session lifecycle endpoints for the API.
+func RegisterSessionRoutes(
+	rg *gin.RouterGroup,
+	getClassSessions gin.HandlerFunc,
+	startClassSession gin.HandlerFunc,
+	getCurrentClassSession gin.HandlerFunc,
+	pauseClassSession gin.HandlerFunc,
+	resumeClassSession gin.HandlerFunc,
+	endClassSession gin.HandlerFunc,
+	lockClassJoins gin.HandlerFunc,
+	unlockClassJoins gin.HandlerFunc,
+	getClassSession gin.HandlerFunc,
+) {
+	rg.GET("/classes/:classId/sessions", getClassSessions)
+	rg.POST("/classes/:classId/sessions", startClassSession)
+	rg.GET("/classes/:classId/sessions/current", getCurrentClassSession)
+	rg.POST("/classes/:classId/sessions/current/pause", pauseClassSession)
+	rg.POST("/classes/:classId/sessions/current/resume", resumeClassSession)
+	rg.POST("/classes/:classId/sessions/current/end", endClassSession)
+	rg.POST("/classes/:classId/sessions/current/lock", lockClassJoins)
+	rg.POST("/classes/:classId/sessions/current/unlock", unlockClassJoins)
+	rg.GET("/classes/:classId/sessions/:sessionId", getClassSession)
+}
+
+// RegisterLayoutRoutes registers seating layout endpoints for the API.
+func RegisterLayoutRoutes(rg *gin.RouterGroup, getSeatingLayout gin.HandlerFunc, updateSeatingLayout gin.HandlerFunc) {
+	rg.GET("/classes/:classId/layout", getSeatingLayout)
+	rg.PUT("/classes/:classId/layout", updateSeatingLayout)
+}
+
+// RegisterGroupRoutes registers student group endpoints for the API.
+func RegisterGroupRoutes(
+	rg *gin.RouterGroup,
+	getClassGroups gin.HandlerFunc,
+	formClassGroups gin.HandlerFunc,
+	getGroupPairings gin.HandlerFunc,
+) {
+	rg.GET("/classes/:classId/groups", getClassGroups)
+	rg.POST("/classes/:classId/groups", formClassGroups)
+	rg.GET("/classes/:classId/groups/pairings", getGroupPairings)
+}
+
+// RegisterPairingConstraintRoutes registers keep-apart and keep-together rule endpoints for the API.
+func RegisterPairingConstraintRoutes(
+	rg *gin.RouterGroup,
+	getPairingConstraints gin.HandlerFunc,
+	addPairingConstraint gin.HandlerFunc,
+	deletePairingConstraint gin.HandlerFunc,
+) {
+	rg.GET("/classes/:classId/pairing-constraints", getPairingConstraints)
+	rg.POST("/classes/:classId/pairing-constraints", addPairingConstraint)
+	rg.DELETE("/classes/:classId/pairing-constraints/:constraintId", deletePairingConstraint)
+}
+
+// RegisterAuthRoutes registers teacher registration and login endpoints for the API.
+func RegisterAuthRoutes(rg *gin.RouterGroup, registerTeacher gin.HandlerFunc, login gin.HandlerFunc) {
+	rg.POST("/auth/register", registerTeacher)
+	rg.POST("/auth/login", login)
+}
+
+// RegisterTokenRoutes registers student session token endpoints for the API.
+func RegisterTokenRoutes(rg *gin.RouterGroup, verifyStudentToken gin.HandlerFunc) {
+	rg.POST("/tokens/verify", verifyStudentToken)
+}
+
+// RegisterProtocolRoutes registers the WebSocket protocol schema endpoint for the API.
+func RegisterProtocolRoutes(rg *gin.RouterGroup, getWebSocketSchema gin.HandlerFunc) {
+	rg.GET("/ws/schema", getWebSocketSchema)
 }
 
 // RegisterHealthRoutes registers health check endpoint for the API.
 func RegisterHealthRoutes(rg *gin.RouterGroup, getHealth gin.HandlerFunc) {
 	rg.GET("/health", getHealth)
 }
+
+// RegisterJoinRequestRoutes registers join approval endpoints for the API.
+// Joining devices follow their request by its poll token without authenticating.
+func RegisterJoinRequestRoutes(
+	rg *gin.RouterGroup,
+	requireClassOwner gin.HandlerFunc,
+	pollJoinRequest gin.HandlerFunc,
+	getJoinRequests gin.HandlerFunc,
+	approveJoinRequest gin.HandlerFunc,
+	rejectJoinRequest gin.HandlerFunc,
+) {
+	rg.GET("/join-requests/:pollToken", pollJoinRequest)
+	rg.GET("/classes/:classId/join-requests", requireClassOwner, getJoinRequests)
+	rg.POST("/classes/:classId/join-requests/:requestId/approve", requireClassOwner, approveJoinRequest)
+	rg.POST("/classes/:classId/join-requests/:requestId/reject", requireClassOwner, rejectJoinRequest)
+}
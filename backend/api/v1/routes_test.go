@@ -13,10 +13,18 @@ func dummyHandler(c *gin.Context) {
 	c.String(200, "ok")
 }
 
+func passThrough(c *gin.Context) {
+	c.Next()
+}
+
+func denyAll(c *gin.Context) {
+	c.AbortWithStatus(http.StatusUnauthorized)
+}
+
 func TestRegisterClassRoutes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	v1.RegisterClassRoutes(r.Group("/api/v1"), dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler)
+	v1.RegisterClassRoutes(r.Group("/api/v1"), passThrough, passThrough, dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler)
 
 	endpoints := []string{
 		"/api/v1/classes",
@@ -35,6 +43,56 @@ func TestRegisterClassRoutes(t *testing.T) {
 			t.Errorf("Route %s did not return expected response", ep)
 		}
 	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/classes/abc/join", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != 200 || w.Body.String() != "ok" {
+		t.Error("Join form submission route did not return expected response")
+	}
+}
+
+func TestRegisterClassRoutes_Protected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterClassRoutes(r.Group("/api/v1"), denyAll, denyAll, dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler)
+
+	routes := []struct {
+		method, path string
+		want         int
+	}{
+		{"GET", "/api/v1/classes", http.StatusUnauthorized},
+		{"GET", "/api/v1/classes/abc", http.StatusUnauthorized},
+		{"GET", "/api/v1/classes/abc/qr", http.StatusUnauthorized},
+		{"GET", "/api/v1/classes/abc/ws", http.StatusUnauthorized},
+		{"GET", "/api/v1/classes/abc/join", http.StatusOK},
+		{"POST", "/api/v1/classes/abc/join", http.StatusOK},
+		{"POST", "/api/v1/classes/abc/leave", http.StatusOK},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != rt.want {
+			t.Errorf("Route %s %s: expected %d, got %d", rt.method, rt.path, rt.want, w.Code)
+		}
+	}
+}
+
+func TestRegisterAuthRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterAuthRoutes(r.Group("/api/v1"), dummyHandler, dummyHandler)
+
+	for _, path := range []string{"/api/v1/auth/register", "/api/v1/auth/login"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != "ok" {
+			t.Errorf("Route POST %s did not return expected response", path)
+		}
+	}
 }
 
 func TestRegisterHealthRoutes(t *testing.T) {
@@ -49,3 +107,290 @@ func TestRegisterHealthRoutes(t *testing.T) {
 		t.Error("Health route did not return expected response")
 	}
 }
+
+func TestRegisterPointRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterPointRoutes(r.Group("/api/v1"), dummyHandler, dummyHandler, dummyHandler)
+
+	routes := []struct{ method, path string }{
+		{"GET", "/api/v1/classes/abc/points"},
+		{"POST", "/api/v1/classes/abc/points/award"},
+		{"POST", "/api/v1/classes/abc/points/deduct"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != "ok" {
+			t.Errorf("Route %s %s did not return expected response", rt.method, rt.path)
+		}
+	}
+}
+
+func TestRegisterLayoutRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	named := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(200, name) }
+	}
+	v1.RegisterLayoutRoutes(r.Group("/api/v1"), named("get"), named("update"))
+
+	routes := []struct{ method, path, want string }{
+		{"GET", "/api/v1/classes/abc/layout", "get"},
+		{"PUT", "/api/v1/classes/abc/layout", "update"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != rt.want {
+			t.Errorf("Route %s %s: expected %q, got %d %q", rt.method, rt.path, rt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRegisterGroupRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	named := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(200, name) }
+	}
+	v1.RegisterGroupRoutes(r.Group("/api/v1"), named("list"), named("form"), named("pairings"))
+
+	routes := []struct{ method, path, want string }{
+		{"GET", "/api/v1/classes/abc/groups", "list"},
+		{"POST", "/api/v1/classes/abc/groups", "form"},
+		{"GET", "/api/v1/classes/abc/groups/pairings", "pairings"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != rt.want {
+			t.Errorf("Route %s %s: expected %q, got %d %q", rt.method, rt.path, rt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRegisterPairingConstraintRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	named := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(200, name) }
+	}
+	v1.RegisterPairingConstraintRoutes(r.Group("/api/v1"), named("list"), named("add"), named("delete"))
+
+	routes := []struct{ method, path, want string }{
+		{"GET", "/api/v1/classes/abc/pairing-constraints", "list"},
+		{"POST", "/api/v1/classes/abc/pairing-constraints", "add"},
+		{"DELETE", "/api/v1/classes/abc/pairing-constraints/7", "delete"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != rt.want {
+			t.Errorf("Route %s %s: expected %q, got %d %q", rt.method, rt.path, rt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRegisterSessionRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	named := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(200, name) }
+	}
+	v1.RegisterSessionRoutes(r.Group("/api/v1"),
+		named("list"), named("start"), named("current"), named("pause"), named("resume"), named("end"),
+		named("lock"), named("unlock"), named("get"))
+
+	routes := []struct{ method, path, want string }{
+		{"GET", "/api/v1/classes/abc/sessions", "list"},
+		{"POST", "/api/v1/classes/abc/sessions", "start"},
+		{"GET", "/api/v1/classes/abc/sessions/current", "current"},
+		{"POST", "/api/v1/classes/abc/sessions/current/pause", "pause"},
+		{"POST", "/api/v1/classes/abc/sessions/current/resume", "resume"},
+		{"POST", "/api/v1/classes/abc/sessions/current/end", "end"},
+		{"POST", "/api/v1/classes/abc/sessions/current/lock", "lock"},
+		{"POST", "/api/v1/classes/abc/sessions/current/unlock", "unlock"},
+		{"GET", "/api/v1/classes/abc/sessions/42", "get"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != rt.want {
+			t.Errorf("Route %s %s returned %d %q, expected %q", rt.method, rt.path, w.Code, w.Body.String(), rt.want)
+		}
+	}
+}
+
+func TestRegisterAttendanceRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	named := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(200, name) }
+	}
+	v1.RegisterAttendanceRoutes(r.Group("/api/v1"), named("attendance"), named("remove"), named("waitlist"))
+
+	routes := []struct{ method, path, want string }{
+		{"GET", "/api/v1/classes/abc/attendance", "attendance"},
+		{"POST", "/api/v1/classes/abc/attendance/remove", "remove"},
+		{"GET", "/api/v1/classes/abc/waitlist", "waitlist"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != rt.want {
+			t.Errorf("Route %s %s: expected %q, got %d %q", rt.method, rt.path, rt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRegisterClassStateRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterClassStateRoutes(r.Group("/api/v1"), dummyHandler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/classes/abc/state", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != 200 || w.Body.String() != "ok" {
+		t.Error("Class state route did not return expected response")
+	}
+}
+
+func TestRegisterProtocolRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterProtocolRoutes(r.Group("/api/v1"), dummyHandler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/ws/schema", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != 200 || w.Body.String() != "ok" {
+		t.Error("WebSocket schema route did not return expected response")
+	}
+}
+
+func TestRegisterClassManagementRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterClassManagementRoutes(r.Group("/api/v1"), passThrough, passThrough, dummyHandler, dummyHandler, dummyHandler, dummyHandler)
+
+	routes := []struct{ method, path string }{
+		{"POST", "/api/v1/classes"},
+		{"PATCH", "/api/v1/classes/abc"},
+		{"POST", "/api/v1/classes/abc/archive"},
+		{"DELETE", "/api/v1/classes/abc"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != "ok" {
+			t.Errorf("Route %s %s did not return expected response", rt.method, rt.path)
+		}
+	}
+}
+
+func TestRegisterStudentAndRosterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterStudentRoutes(r.Group("/api/v1"), passThrough, passThrough, dummyHandler, dummyHandler, dummyHandler)
+	v1.RegisterRosterRoutes(r.Group("/api/v1"), dummyHandler, dummyHandler, dummyHandler, dummyHandler, dummyHandler)
+
+	routes := []struct{ method, path string }{
+		{"POST", "/api/v1/students"},
+		{"PATCH", "/api/v1/students/1"},
+		{"DELETE", "/api/v1/students/1"},
+		{"GET", "/api/v1/classes/abc/students"},
+		{"POST", "/api/v1/classes/abc/students"},
+		{"DELETE", "/api/v1/classes/abc/students/1"},
+		{"POST", "/api/v1/classes/abc/students/import"},
+		{"POST", "/api/v1/classes/abc/students/promote"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != "ok" {
+			t.Errorf("Route %s %s did not return expected response", rt.method, rt.path)
+		}
+	}
+}
+
+func TestRegisterTokenRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterTokenRoutes(r.Group("/api/v1"), dummyHandler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/tokens/verify", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != 200 || w.Body.String() != "ok" {
+		t.Errorf("Route POST /api/v1/tokens/verify did not return expected response")
+	}
+}
+
+func TestRegisterJoinRequestRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	named := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(200, name) }
+	}
+	v1.RegisterJoinRequestRoutes(r.Group("/api/v1"), passThrough,
+		named("poll"), named("list"), named("approve"), named("reject"))
+
+	routes := []struct{ method, path, want string }{
+		{"GET", "/api/v1/join-requests/token123", "poll"},
+		{"GET", "/api/v1/classes/abc/join-requests", "list"},
+		{"POST", "/api/v1/classes/abc/join-requests/7/approve", "approve"},
+		{"POST", "/api/v1/classes/abc/join-requests/7/reject", "reject"},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != rt.want {
+			t.Errorf("Route %s %s: expected %q, got %d %q", rt.method, rt.path, rt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRegisterJoinRequestRoutes_Protected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1.RegisterJoinRequestRoutes(r.Group("/api/v1"), denyAll, dummyHandler, dummyHandler, dummyHandler, dummyHandler)
+
+	routes := []struct {
+		method, path string
+		want         int
+	}{
+		{"GET", "/api/v1/join-requests/token123", http.StatusOK},
+		{"GET", "/api/v1/classes/abc/join-requests", http.StatusUnauthorized},
+		{"POST", "/api/v1/classes/abc/join-requests/7/approve", http.StatusUnauthorized},
+		{"POST", "/api/v1/classes/abc/join-requests/7/reject", http.StatusUnauthorized},
+	}
+
+	for _, rt := range routes {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(rt.method, rt.path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != rt.want {
+			t.Errorf("Route %s %s: expected %d, got %d", rt.method, rt.path, rt.want, w.Code)
+		}
+	}
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
+
 	"classswift-backend/config"
 	"classswift-backend/internal/handler"
 	"classswift-backend/internal/middleware"
 	"classswift-backend/internal/service"
 	"classswift-backend/pkg/database"
 	"classswift-backend/pkg/logger"
+	"classswift-backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -35,8 +38,34 @@ func main() {
 		panic(err)
 	}
 
-	// Initialize WebSocket hub
-	service.InitWebSocketHub()
+	// Initialize WebSocket hub, fanning events out through Redis when running multiple replicas
+	if redisURL := config.RedisURL(); redisURL != "" {
+		// Replicas must share the token secret, or tokens issued by one are rejected by the others
+		if config.SessionTokenSecretGenerated() {
+			err := errors.New("SESSION_TOKEN_SECRET must be set when REDIS_URL is")
+			logger.Errorf("Refusing to start: %v", err)
+			panic(err)
+		}
+		broadcaster, err := utils.NewRedisBroadcaster(redisURL)
+		if err != nil {
+			logger.Errorf("Failed to initialize Redis broadcaster: %v", err)
+			panic(err)
+		}
+		err = service.InitWebSocketHubWithBroadcaster(broadcaster)
+		if err != nil {
+			logger.Errorf("Failed to initialize WebSocket hub: %v", err)
+			panic(err)
+		}
+		logger.Info("WebSocket events are fanned out through Redis")
+	} else if err := service.InitWebSocketHub(); err != nil {
+		logger.Errorf("Failed to initialize WebSocket hub: %v", err)
+		panic(err)
+	}
+
+	// Announce the automatic join locks of sessions left open by an earlier run
+	if err := service.ScheduleAutoLocks(db); err != nil {
+		logger.Errorf("Failed to schedule automatic join locks: %v", err)
+	}
 
 	// Create Gin router
 	r := gin.Default()
@@ -51,16 +80,123 @@ func main() {
 	// Health check route
 	r.GET("/health", handler.GetHealth)
 
+	// Teacher authentication routes
+	v1.RegisterAuthRoutes(r.Group("/api/v1"), handler.RegisterTeacher, handler.Login)
+
+	requireTeacher := middleware.RequireTeacher()
+	requireClassOwner := middleware.RequireClassOwner()
+
 	// Class routes
 	v1.RegisterClassRoutes(
 		r.Group("/api/v1"),
+		requireTeacher,
+		requireClassOwner,
 		handler.GetClasses,
 		handler.GetClass,
 		handler.GetClassQRCode,
 		handler.HandleStudentJoin,
+		handler.SubmitStudentJoin,
+		handler.LeaveClass,
 		handler.HandleWebSocket,
 	)
 
+	// Class management routes
+	v1.RegisterClassManagementRoutes(
+		r.Group("/api/v1"),
+		requireTeacher,
+		requireClassOwner,
+		handler.CreateClass,
+		handler.UpdateClass,
+		handler.ArchiveClass,
+		handler.DeleteClass,
+	)
+
+	// Student and roster routes
+	v1.RegisterStudentRoutes(
+		r.Group("/api/v1"),
+		requireTeacher,
+		middleware.RequireStudentOwner(),
+		handler.CreateStudent,
+		handler.RenameStudent,
+		handler.DeleteStudent,
+	)
+	v1.RegisterRosterRoutes(
+		r.Group("/api/v1", requireClassOwner),
+		handler.GetClassStudents,
+		handler.EnrollStudent,
+		handler.UnenrollStudent,
+		handler.ImportClassStudents,
+		handler.PromoteGuest,
+	)
+
+	// Class session routes
+	v1.RegisterSessionRoutes(
+		r.Group("/api/v1", requireClassOwner),
+		handler.GetClassSessions,
+		handler.StartClassSession,
+		handler.GetCurrentClassSession,
+		handler.PauseClassSession,
+		handler.ResumeClassSession,
+		handler.EndClassSession,
+		handler.LockClassJoins,
+		handler.UnlockClassJoins,
+		handler.GetClassSession,
+	)
+
+	// Attendance routes
+	v1.RegisterAttendanceRoutes(
+		r.Group("/api/v1", requireClassOwner),
+		handler.GetClassAttendance,
+		handler.RemoveFromAttendance,
+		handler.GetClassWaitlist,
+	)
+
+	// Live class state routes
+	v1.RegisterClassStateRoutes(r.Group("/api/v1", requireClassOwner), handler.GetClassState)
+
+	// Point ledger routes
+	v1.RegisterPointRoutes(
+		r.Group("/api/v1", requireClassOwner),
+		handler.GetClassPoints,
+		handler.AwardPoints,
+		handler.DeductPoints,
+	)
+
+	// Seating layout routes
+	v1.RegisterLayoutRoutes(r.Group("/api/v1", requireClassOwner), handler.GetSeatingLayout, handler.UpdateSeatingLayout)
+
+	// Student group routes
+	v1.RegisterGroupRoutes(
+		r.Group("/api/v1", requireClassOwner),
+		handler.GetClassGroups,
+		handler.FormClassGroups,
+		handler.GetGroupPairings,
+	)
+
+	// Pairing constraint routes
+	v1.RegisterPairingConstraintRoutes(
+		r.Group("/api/v1", requireClassOwner),
+		handler.GetPairingConstraints,
+		handler.AddPairingConstraint,
+		handler.DeletePairingConstraint,
+	)
+
+	// Join approval routes
+	v1.RegisterJoinRequestRoutes(
+		r.Group("/api/v1"),
+		requireClassOwner,
+		handler.PollJoinRequest,
+		handler.GetJoinRequests,
+		handler.ApproveJoinRequest,
+		handler.RejectJoinRequest,
+	)
+
+	// WebSocket protocol schema route
+	v1.RegisterProtocolRoutes(r.Group("/api/v1"), handler.GetWebSocketSchema)
+
+	// Student session token routes
+	v1.RegisterTokenRoutes(r.Group("/api/v1"), handler.VerifyStudentToken)
+
 	logger.Infof("Starting ClassSwift API server on port %s", config.Port())
 
 	// Start server
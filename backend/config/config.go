@@ -1,8 +1,12 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -26,6 +30,18 @@ type Config struct {
 	BaseURL string
 	// CORSOrigins is a comma-separated list of allowed CORS origins.
 	CORSOrigins string
+	// AttendanceLateAfter is how long after session start a join is recorded as late.
+	AttendanceLateAfter time.Duration
+	// SessionTokenSecret is the HMAC key used to sign student session and teacher access tokens.
+	SessionTokenSecret string
+	// SessionTokenSecretGenerated is set when SESSION_TOKEN_SECRET is unset and a per-process key is used instead.
+	SessionTokenSecretGenerated bool
+	// SessionTokenTTL is how long a student session token stays valid after it is issued.
+	SessionTokenTTL time.Duration
+	// TeacherTokenTTL is how long a teacher access token stays valid after login.
+	TeacherTokenTTL time.Duration
+	// RedisURL is the Redis server used to fan WebSocket events out across replicas (empty for in-process only).
+	RedisURL string
 }
 
 var (
@@ -52,15 +68,29 @@ func Init() {
 		port := getEnv("PORT", "3000")
 		baseURL := proto + "://" + host + ":" + port
 
+		// Without a configured secret, tokens are signed with a per-process key and
+		// do not survive restarts, nor work on other replicas.
+		sessionTokenSecret := getEnv("SESSION_TOKEN_SECRET", "")
+		sessionTokenSecretGenerated := sessionTokenSecret == ""
+		if sessionTokenSecretGenerated {
+			sessionTokenSecret = randomSecret()
+		}
+
 		cfg = &Config{
-			Port:                    port,
-			DatabaseURL:             getEnv("DATABASE_URL", ""),
-			GinMode:                 getEnv("GIN_MODE", "release"),
-			Host:                    host,
-			TLSMode:                 tlsMode,
-			ClassRedirectionBaseURL: getEnv("CLASS_REDIRECTION_BASE_URL", "https://www.classswift.viewsonic.io"),
-			BaseURL:                 baseURL,
-			CORSOrigins:             getEnv("CORS_ORIGINS", ""),
+			Port:                        port,
+			DatabaseURL:                 getEnv("DATABASE_URL", ""),
+			GinMode:                     getEnv("GIN_MODE", "release"),
+			Host:                        host,
+			TLSMode:                     tlsMode,
+			ClassRedirectionBaseURL:     getEnv("CLASS_REDIRECTION_BASE_URL", "https://www.classswift.viewsonic.io"),
+			BaseURL:                     baseURL,
+			CORSOrigins:                 getEnv("CORS_ORIGINS", ""),
+			AttendanceLateAfter:         time.Duration(getEnvInt("ATTENDANCE_LATE_AFTER_MINUTES", 10)) * time.Minute,
+			SessionTokenSecret:          sessionTokenSecret,
+			SessionTokenSecretGenerated: sessionTokenSecretGenerated,
+			SessionTokenTTL:             time.Duration(getEnvInt("SESSION_TOKEN_TTL_MINUTES", 240)) * time.Minute,
+			TeacherTokenTTL:             time.Duration(getEnvInt("TEACHER_TOKEN_TTL_MINUTES", 720)) * time.Minute,
+			RedisURL:                    getEnv("REDIS_URL", ""),
 		}
 	})
 }
@@ -72,6 +102,23 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvInt(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func randomSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic("config: failed to generate session token secret: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
 // BaseURL returns the computed full base URL (protocol + host + port) for the backend.
 func BaseURL() string {
 	if cfg == nil {
@@ -119,3 +166,53 @@ func DatabaseURL() string {
 	}
 	return cfg.DatabaseURL
 }
+
+// AttendanceLateAfter returns how long after session start a join is recorded as late.
+func AttendanceLateAfter() time.Duration {
+	if cfg == nil {
+		panic("config.Init() must be called before config.AttendanceLateAfter()")
+	}
+	return cfg.AttendanceLateAfter
+}
+
+// SessionTokenSecret returns the HMAC key used to sign student session tokens.
+func SessionTokenSecret() []byte {
+	if cfg == nil {
+		panic("config.Init() must be called before config.SessionTokenSecret()")
+	}
+	return []byte(cfg.SessionTokenSecret)
+}
+
+// SessionTokenSecretGenerated reports whether tokens are signed with a per-process key
+// because SESSION_TOKEN_SECRET is not set.
+func SessionTokenSecretGenerated() bool {
+	if cfg == nil {
+		panic("config.Init() must be called before config.SessionTokenSecretGenerated()")
+	}
+	return cfg.SessionTokenSecretGenerated
+}
+
+// SessionTokenTTL returns how long a student session token stays valid after it is issued.
+func SessionTokenTTL() time.Duration {
+	if cfg == nil {
+		panic("config.Init() must be called before config.SessionTokenTTL()")
+	}
+	return cfg.SessionTokenTTL
+}
+
+// TeacherTokenTTL returns how long a teacher access token stays valid after login.
+func TeacherTokenTTL() time.Duration {
+	if cfg == nil {
+		panic("config.Init() must be called before config.TeacherTokenTTL()")
+	}
+	return cfg.TeacherTokenTTL
+}
+
+// RedisURL returns the Redis server used to fan WebSocket events out across replicas.
+// It is empty when WebSocket events are only delivered within this process.
+func RedisURL() string {
+	if cfg == nil {
+		panic("config.Init() must be called before config.RedisURL()")
+	}
+	return cfg.RedisURL
+}
@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"classswift-backend/config"
 )
@@ -16,3 +17,11 @@ func TestInitAndBaseURL(t *testing.T) {
 		t.Errorf("Expected base URL to be http://testhost:1234, got %s", config.BaseURL())
 	}
 }
+
+func TestAttendanceLateAfterDefault(t *testing.T) {
+	os.Unsetenv("ATTENDANCE_LATE_AFTER_MINUTES")
+	config.Init()
+	if config.AttendanceLateAfter() != 10*time.Minute {
+		t.Errorf("Expected default late threshold of 10m, got %s", config.AttendanceLateAfter())
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// GetClassAttendance handles GET /api/v1/classes/:classId/attendance
+// Reports the current session, or the session given by ?sessionId=.
+func GetClassAttendance(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	session, ok := resolveSession(c, db, class)
+	if !ok {
+		return
+	}
+
+	report, err := service.GetAttendanceReport(db, class.ID, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve attendance",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    report,
+		Message: "Attendance retrieved successfully",
+	})
+}
+
+// GetClassWaitlist handles GET /api/v1/classes/:classId/waitlist
+// Lists who is waiting for a seat in the current session, or the session given by ?sessionId=.
+func GetClassWaitlist(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	session, ok := resolveSession(c, db, class)
+	if !ok {
+		return
+	}
+
+	entries, err := service.ListWaitlist(db, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve waiting list",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    entries,
+		Message: "Waiting list retrieved successfully",
+	})
+}
+
+// RemoveFromAttendance handles POST /api/v1/classes/:classId/attendance/remove
+// Takes an enrolled student (studentId) or a guest (seatNumber) out of the current session.
+func RemoveFromAttendance(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.RemoveStudentCommand
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	record, err := service.RemoveStudent(db, class, req)
+	if err != nil {
+		respondLeaveError(c, class, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    record,
+		Message: "Student removed successfully",
+	})
+}
+
+// LeaveClass handles POST /api/v1/classes/:classId/leave
+// The student identifies themselves with the session token from their join, which stops working once they leave.
+func LeaveClass(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	claims, ok := bindStudentToken(c)
+	if !ok {
+		return
+	}
+
+	record, err := service.LeaveClass(db, class, claims)
+	if err != nil {
+		respondLeaveError(c, class, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    record,
+		Message: "Left the class successfully",
+	})
+}
+
+// respondLeaveError maps a failure to take someone out of a session to its response.
+func respondLeaveError(c *gin.Context, class *model.Class, err error) {
+	switch {
+	case errors.Is(err, service.ErrNoActiveSession):
+		respondSessionError(c, err)
+	case errors.Is(err, service.ErrNotInSession):
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Not in the current session",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrInvalidToken):
+		c.JSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "Invalid or expired session token",
+		})
+	default:
+		logger.Errorf("Failed to take a student out of class %s: %v", class.PublicID, err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to leave the session",
+			Errors:  []string{err.Error()},
+		})
+	}
+}
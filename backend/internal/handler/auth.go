@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/internal/middleware"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+)
+
+// RegisterTeacher handles POST /api/v1/auth/register
+func RegisterTeacher(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.RegisterTeacherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	teacher, err := service.RegisterTeacher(db, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidEmail),
+			errors.Is(err, service.ErrInvalidTeacherName),
+			errors.Is(err, service.ErrWeakPassword):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrEmailTaken):
+			status = http.StatusConflict
+		}
+		c.JSON(status, model.APIResponse{
+			Success: false,
+			Message: "Failed to register teacher",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	respondWithTeacherToken(c, http.StatusCreated, teacher, "Teacher registered successfully")
+}
+
+// Login handles POST /api/v1/auth/login
+func Login(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	teacher, err := service.AuthenticateTeacher(db, req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, model.APIResponse{
+				Success: false,
+				Message: "Invalid email or password",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to log in",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	respondWithTeacherToken(c, http.StatusOK, teacher, "Logged in successfully")
+}
+
+// respondWithTeacherToken issues an access token for the teacher and writes it with the teacher profile.
+func respondWithTeacherToken(c *gin.Context, status int, teacher *model.Teacher, message string) {
+	signed, claims, err := service.IssueTeacherToken(teacher)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to issue access token",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(status, model.APIResponse{
+		Success: true,
+		Data: model.AuthResponse{
+			Token:     signed,
+			ExpiresAt: claims.ExpiresAt,
+			Teacher:   teacher,
+		},
+		Message: message,
+	})
+}
+
+// currentTeacher returns the authenticated teacher's claims, responding 401 if there are none.
+func currentTeacher(c *gin.Context) (*model.TeacherClaims, bool) {
+	claims, ok := middleware.CurrentTeacher(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return nil, false
+	}
+	return claims, true
+}
+
+// currentTeacherID returns the authenticated teacher's ID, responding 401 if there is none.
+func currentTeacherID(c *gin.Context) (uint, bool) {
+	claims, ok := currentTeacher(c)
+	if !ok {
+		return 0, false
+	}
+	return claims.TeacherID, true
+}
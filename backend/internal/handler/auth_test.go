@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+)
+
+// teacherToken issues an access token for a teacher with the given ID.
+func teacherToken(t *testing.T, teacherID uint) string {
+	t.Helper()
+	signed, _, err := service.IssueTeacherToken(&model.Teacher{ID: teacherID, Email: "teacher@example.com"})
+	if err != nil {
+		t.Fatalf("failed to issue teacher token: %v", err)
+	}
+	return signed
+}
+
+func TestRegisterTeacher_WeakPassword(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/auth/register", strings.NewReader(`{"email": "ada@example.com", "name": "Ada", "password": "short"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.RegisterTeacher(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for weak password, got %d", w.Code)
+	}
+}
+
+func TestLogin_MissingFields(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/auth/login", strings.NewReader(`{"email": "ada@example.com"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.Login(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for missing password, got %d", w.Code)
+	}
+}
+
+func TestGetClasses_RequiresTeacher(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/classes", nil)
+
+	handler.GetClasses(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without an authenticated teacher, got %d", w.Code)
+	}
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"classswift-backend/config"
 	"classswift-backend/internal/model"
@@ -13,6 +16,15 @@ import (
 	"classswift-backend/pkg/logger"
 )
 
+// respondClassNotFound writes the standard 404 response for an unknown class public ID.
+func respondClassNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, model.APIResponse{
+		Success: false,
+		Message: "Class not found",
+		Errors:  []string{"Class with the specified ID does not exist"},
+	})
+}
+
 // GetClass handles GET /api/v1/classes/:classId
 func GetClass(c *gin.Context) {
 	db := database.GetDB()
@@ -20,11 +32,7 @@ func GetClass(c *gin.Context) {
 
 	class, err := service.GetClassByPublicID(db, classID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, model.APIResponse{
-			Success: false,
-			Message: "Class not found",
-			Errors:  []string{"Class with the specified ID does not exist"},
-		})
+		respondClassNotFound(c)
 		return
 	}
 
@@ -41,9 +49,14 @@ func GetClass(c *gin.Context) {
 }
 
 // GetClasses handles GET /api/v1/classes
+// Only classes owned by the authenticated teacher are listed.
 func GetClasses(c *gin.Context) {
 	db := database.GetDB()
-	classes, err := service.GetClasses(db)
+	teacherID, ok := currentTeacherID(c)
+	if !ok {
+		return
+	}
+	classes, err := service.GetClasses(db, teacherID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.APIResponse{
 			Success: false,
@@ -66,11 +79,7 @@ func GetClassQRCode(c *gin.Context) {
 
 	class, err := service.GetClassByPublicID(db, classID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, model.APIResponse{
-			Success: false,
-			Message: "Class not found",
-			Errors:  []string{"Class with the specified ID does not exist"},
-		})
+		respondClassNotFound(c)
 		return
 	}
 
@@ -102,53 +111,405 @@ func GetClassQRCode(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// joinPageData is the view model for the join landing page.
+// PollURL is set while the student waits for the teacher to approve their join.
+// Taken counts the seats taken in the open session, the same count joins are admitted against.
+// Locked replaces the form with the class locked message, and Choices asks which of the students
+// sharing the typed name the joiner is.
+type joinPageData struct {
+	Class   *model.Class
+	Taken   int
+	Full    bool
+	Locked  bool
+	Name    string
+	Error   string
+	Notice  string
+	PollURL string
+	Choices []model.NameChoice
+}
+
+func newJoinPageData(class *model.Class, taken int) joinPageData {
+	return joinPageData{
+		Class: class,
+		Taken: taken,
+		Full:  taken >= class.TotalCapacity,
+	}
+}
+
+// loadJoinPageData builds the join landing page of a class with the seats taken in its open session.
+func loadJoinPageData(db *gorm.DB, class *model.Class) joinPageData {
+	taken, err := service.SeatsTaken(db, class.ID)
+	if err != nil {
+		logger.Errorf("Failed to count seats taken for class %s: %v", class.PublicID, err)
+	}
+	return newJoinPageData(class, taken)
+}
+
 // HandleStudentJoin handles GET /api/v1/classes/:classId/join
+// API clients join directly by sending the X-Student-Name header; browsers opening
+// the QR code link without it are served the join landing page instead.
+// Joins to a class requiring approval are queued and answered with the request to follow.
+// A name shared by several students of the class is answered with the choices to pick from,
+// and the join is sent again with the picked student's ID in X-Student-ID.
 func HandleStudentJoin(c *gin.Context) {
 	db := database.GetDB()
 	classPublicID := c.Param("classId")
 
-	studentName := c.GetHeader("X-Student-Name")
+	studentName := service.CleanStudentName(c.GetHeader("X-Student-Name"))
+	chosenStudentID := parseChosenStudentID(c.GetHeader("X-Student-ID"))
+	class, err := service.GetClassByPublicID(db, classPublicID)
+	if err != nil {
+		if studentName == "" {
+			renderPage(c, http.StatusNotFound, "join.html", joinPageData{})
+			return
+		}
+		respondJoinError(c, err)
+		return
+	}
 	if studentName == "" {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
-			Success: false,
-			Message: "Student name is required",
-			Errors:  []string{"Missing 'X-Student-Name' header"},
+		page := loadJoinPageData(db, class)
+		page.Locked, err = service.JoinsLocked(db, class.ID)
+		if err != nil {
+			logger.Errorf("Failed to check join lock for class %s: %v", class.PublicID, err)
+		}
+		if page.Locked {
+			renderPage(c, http.StatusForbidden, "join.html", page)
+			return
+		}
+		renderPage(c, http.StatusOK, "join.html", page)
+		return
+	}
+
+	if class.RequireApproval {
+		request, err := service.RequestJoin(db, class, studentName, chosenStudentID)
+		if err != nil {
+			respondJoinError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, model.APIResponse{
+			Success: true,
+			Code:    model.JoinCodeApprovalPending,
+			Data:    joinRequestStatus(request),
+			Message: "Join request is waiting for the teacher's approval",
 		})
 		return
 	}
 
-	// Find student and get their preferred seat
-	student, preferredSeat, err := service.FindStudentPreferredSeat(db, studentName, classPublicID)
+	redirectURL, err := joinClass(db, class.PublicID, studentName, chosenStudentID)
+	if err != nil {
+		respondJoinError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// SubmitStudentJoin handles POST /api/v1/classes/:classId/join from the join landing page form.
+func SubmitStudentJoin(c *gin.Context) {
+	db := database.GetDB()
+	classPublicID := c.Param("classId")
+
+	class, err := service.GetClassByPublicID(db, classPublicID)
 	if err != nil {
+		renderPage(c, http.StatusNotFound, "join.html", joinPageData{})
+		return
+	}
+
+	page := loadJoinPageData(db, class)
+	page.Name = service.CleanStudentName(c.PostForm("name"))
+	chosenStudentID := parseChosenStudentID(c.PostForm("studentId"))
+	if page.Name == "" {
+		page.Error = "Please enter your name to join the class."
+		renderPage(c, http.StatusBadRequest, "join.html", page)
+		return
+	}
+
+	if class.RequireApproval {
+		request, err := service.RequestJoin(db, class, page.Name, chosenStudentID)
+		if err != nil {
+			renderJoinError(c, page, err)
+			return
+		}
+		page.PollURL = joinRequestStatus(request).PollURL
+		page.Notice = "Your teacher has been asked to let you in. Please keep this page open."
+		renderPage(c, http.StatusAccepted, "join.html", page)
+		return
+	}
+
+	redirectURL, err := joinClass(db, class.PublicID, page.Name, chosenStudentID)
+	if err != nil {
+		renderJoinError(c, page, err)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, redirectURL)
+}
+
+// renderJoinError shows the join landing page again with the reason a join failed.
+func renderJoinError(c *gin.Context, page joinPageData, err error) {
+	var waitlisted *service.WaitlistedError
+	var ambiguous *service.AmbiguousNameError
+	switch {
+	case errors.As(err, &ambiguous):
+		page.Notice = fmt.Sprintf("More than one student in this class is called %s. Which one are you?", page.Name)
+		page.Choices = ambiguous.Choices
+		renderPage(c, http.StatusConflict, "join.html", page)
+	case errors.As(err, &waitlisted):
+		page.Notice = fmt.Sprintf("The class is full. You are number %d on the waiting list; join again when a seat frees up.", waitlisted.Position)
+		renderPage(c, http.StatusAccepted, "join.html", page)
+	case errors.Is(err, service.ErrClassInactive):
+		page.Error = "This class is not accepting students right now."
+		renderPage(c, http.StatusForbidden, "join.html", page)
+	case errors.Is(err, service.ErrNoActiveSession):
+		page.Error = "This class has not started yet. Please wait for your teacher to start it."
+		renderPage(c, http.StatusConflict, "join.html", page)
+	case errors.Is(err, service.ErrClassLocked):
+		page.Locked = true
+		renderPage(c, http.StatusForbidden, "join.html", page)
+	case errors.Is(err, service.ErrClassFull):
+		page.Error = "The class is full, so no more students can join."
+		renderPage(c, http.StatusConflict, "join.html", page)
+	case errors.Is(err, service.ErrJoinRequestPending):
+		page.Error = "Someone is already waiting to join under this name. Please ask your teacher."
+		renderPage(c, http.StatusConflict, "join.html", page)
+	default:
+		logger.Errorf("Failed to process student join for class %s: %v", page.Class.PublicID, err)
+		page.Error = "We could not add you to the class. Please try again."
+		renderPage(c, http.StatusInternalServerError, "join.html", page)
+	}
+}
+
+// respondJoinError maps join errors to API responses, with a code telling refused joins apart.
+func respondJoinError(c *gin.Context, err error) {
+	var waitlisted *service.WaitlistedError
+	var ambiguous *service.AmbiguousNameError
+	switch {
+	case errors.As(err, &ambiguous):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Code:    model.JoinCodeNameAmbiguous,
+			Data:    ambiguous.Choices,
+			Message: "Several students have this name; send the chosen studentId in X-Student-ID",
+			Errors:  []string{err.Error()},
+		})
+	case errors.As(err, &waitlisted):
+		c.JSON(http.StatusAccepted, model.APIResponse{
+			Success: false,
+			Code:    model.JoinCodeWaitlisted,
+			Data:    model.WaitlistPosition{SessionID: waitlisted.SessionID, Position: waitlisted.Position},
+			Message: "Class is full; added to the waiting list",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrClassInactive):
+		c.JSON(http.StatusForbidden, model.APIResponse{
+			Success: false,
+			Code:    model.JoinCodeClassInactive,
+			Message: "Class is not accepting students",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrNoActiveSession):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Code:    model.JoinCodeClassNotStarted,
+			Message: "Class has not started",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrClassLocked):
+		c.JSON(http.StatusForbidden, model.APIResponse{
+			Success: false,
+			Code:    model.JoinCodeClassLocked,
+			Message: "Class is locked",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrClassFull):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Code:    model.JoinCodeClassFull,
+			Message: "Class is full",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrJoinRequestPending):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Join request already pending",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		respondClassNotFound(c)
+	default:
 		c.JSON(http.StatusInternalServerError, model.APIResponse{
 			Success: false,
 			Message: "Failed to process student join",
 			Errors:  []string{err.Error()},
 		})
+	}
+}
+
+// parseChosenStudentID reads the student a joiner picked between students sharing their name.
+// A missing or malformed value counts as no pick, so the choices are offered again.
+func parseChosenStudentID(raw string) *uint {
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil
+	}
+	chosen := uint(id)
+	return &chosen
+}
+
+// joinClass records the joining student, notifies the teacher dashboard and returns the
+// student app URL carrying the student's session token.
+func joinClass(db *gorm.DB, classPublicID string, studentName string, chosenStudentID *uint) (string, error) {
+	result, err := service.JoinClass(db, classPublicID, studentName, chosenStudentID)
+	if err != nil {
+		return "", err
+	}
+	service.AnnounceJoin(result, result.Name)
+
+	signed, _, err := service.IssueStudentToken(result, result.Name)
+	if err != nil {
+		return "", err
+	}
+	return service.StudentRedirectURL(config.ClassRedirectionBaseURL(), signed)
+}
+
+// CreateClass handles POST /api/v1/classes
+// The authenticated teacher becomes the owner of the new class.
+func CreateClass(c *gin.Context) {
+	db := database.GetDB()
+	teacherID, ok := currentTeacherID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CreateClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	class, err := service.CreateClass(db, teacherID, req)
+	if err != nil {
+		respondClassWriteError(c, err, "Failed to create class")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Data: model.ClassResponse{
+			Class:    *class,
+			JoinLink: fmt.Sprintf("%s/api/v1/classes/%s/join", config.BaseURL(), class.PublicID),
+		},
+		Message: "Class created successfully",
+	})
+}
+
+// UpdateClass handles PATCH /api/v1/classes/:classId
+func UpdateClass(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.UpdateClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	class, err = service.UpdateClass(db, class.ID, req)
+	if err != nil {
+		respondClassWriteError(c, err, "Failed to update class")
 		return
 	}
 
-	// Prepare joining student data
-	seatNumber := 0
-	joiningStudentData := map[string]interface{}{
-		"name":       studentName,
-		"seatNumber": seatNumber,
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    class,
+		Message: "Class updated successfully",
+	})
+}
+
+// ArchiveClass handles POST /api/v1/classes/:classId/archive
+func ArchiveClass(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
 	}
-	if preferredSeat != nil {
-		seatNumber = preferredSeat.PreferredSeatNumber
-		joiningStudentData["seatNumber"] = seatNumber
+
+	class, err = service.ArchiveClass(db, class.ID)
+	if err != nil {
+		respondClassWriteError(c, err, "Failed to archive class")
+		return
 	}
-	// If student is registered, add id
-	if student != nil {
-		joiningStudentData["id"] = student.ID
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    class,
+		Message: "Class archived successfully",
+	})
+}
+
+// DeleteClass handles DELETE /api/v1/classes/:classId
+func DeleteClass(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
 	}
 
-	// Broadcast WebSocket message
-	classUpdateData := map[string]interface{}{
-		"joiningStudent": joiningStudentData,
+	if err := service.DeleteClass(db, class.ID); err != nil {
+		respondClassWriteError(c, err, "Failed to delete class")
+		return
 	}
 
-	service.BroadcastClassUpdate(classPublicID, "class_updated", classUpdateData)
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Class deleted successfully",
+	})
+}
 
-	c.Redirect(http.StatusFound, config.ClassRedirectionBaseURL())
+// respondClassWriteError maps class management service errors to API responses.
+func respondClassWriteError(c *gin.Context, err error, failureMessage string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidClassName),
+		errors.Is(err, service.ErrInvalidCapacity),
+		errors.Is(err, service.ErrCapacityBelowEnrollment),
+		errors.Is(err, service.ErrInvalidAutoLock):
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid class",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrLayoutExcludesEnrolledSeat):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Capacity conflicts with enrolled students' seats",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		respondClassNotFound(c)
+	default:
+		logger.Errorf("%s: %v", failureMessage, err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	}
 }
@@ -3,9 +3,11 @@ package handler_test
 import (
 	"classswift-backend/config"
 	"classswift-backend/internal/handler"
+	"classswift-backend/internal/middleware"
 	"classswift-backend/pkg/database"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -27,7 +29,6 @@ func setupMockDB(t *testing.T) *gorm.DB {
 	return gormDB
 }
 
-
 func TestGetClassQRCode_NotFound(t *testing.T) {
 	config.Init()
 	database.SetDB(setupMockDB(t))
@@ -57,3 +58,76 @@ func TestHandleStudentJoin_NotFound(t *testing.T) {
 		t.Errorf("Expected 400 or 500 for missing class, got %d", w.Code)
 	}
 }
+
+func TestHandleStudentJoin_LandingPageNotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("GET", "/classes/nonexistent/join", nil)
+
+	handler.HandleStudentJoin(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+	if !strings.Contains(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("Expected HTML landing page, got Content-Type %q", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "Class not found") {
+		t.Errorf("Expected not found page, got %s", w.Body.String())
+	}
+}
+
+func TestSubmitStudentJoin_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("POST", "/classes/nonexistent/join", strings.NewReader("name=TestStudent"))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	handler.SubmitStudentJoin(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
+
+func TestCreateClass_InvalidCapacity(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/classes", middleware.RequireTeacher(), handler.CreateClass)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/classes", strings.NewReader(`{"name": "302 Science", "totalCapacity": 0}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+teacherToken(t, 1))
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid capacity, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "total capacity must be greater than zero") {
+		t.Errorf("Expected capacity error, got %s", w.Body.String())
+	}
+}
+
+func TestDeleteClass_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("DELETE", "/classes/nonexistent", nil)
+
+	handler.DeleteClass(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
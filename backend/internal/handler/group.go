@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// GetClassGroups handles GET /api/v1/classes/:classId/groups
+// Returns the groups of the current session, or of the session given by ?sessionId=.
+func GetClassGroups(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	session, ok := resolveSession(c, db, class)
+	if !ok {
+		return
+	}
+
+	groups, err := service.ListSessionGroups(db, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve groups",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    groups,
+		Message: "Groups retrieved successfully",
+	})
+}
+
+// GetGroupPairings handles GET /api/v1/classes/:classId/groups/pairings
+// Returns how many sessions each pair of enrolled students has shared a group in.
+func GetGroupPairings(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	matrix, err := service.GetPairingMatrix(db, class)
+	if err != nil {
+		logger.Errorf("Failed to build pairing matrix for class %s: %v", class.PublicID, err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve group pairings",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    matrix,
+		Message: "Group pairings retrieved successfully",
+	})
+}
+
+// FormClassGroups handles POST /api/v1/classes/:classId/groups
+// Regroups the current session; an empty body forms sequential groups of the default size.
+func FormClassGroups(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.GroupingRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	groups, err := service.FormGroups(db, class, req)
+	if err != nil {
+		respondGroupError(c, class, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Data:    groups,
+		Message: "Groups formed successfully",
+	})
+}
+
+// respondGroupError maps grouping service errors to API responses.
+// Unsatisfiable pairing constraints are listed in Data, with one reason per constraint in Errors.
+func respondGroupError(c *gin.Context, class *model.Class, err error) {
+	var constraintsErr *service.PairingConstraintsError
+	switch {
+	case errors.Is(err, service.ErrNoActiveSession):
+		respondSessionError(c, err)
+	case errors.Is(err, service.ErrInvalidGrouping), errors.Is(err, service.ErrUnknownGroupingStrategy):
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid grouping request",
+			Errors:  []string{err.Error()},
+		})
+	case errors.As(err, &constraintsErr):
+		reasons := make([]string, 0, len(constraintsErr.Unsatisfied))
+		for _, unsatisfied := range constraintsErr.Unsatisfied {
+			reasons = append(reasons, unsatisfied.Reason)
+		}
+		c.JSON(http.StatusUnprocessableEntity, model.APIResponse{
+			Success: false,
+			Data:    constraintsErr.Unsatisfied,
+			Message: "Pairing constraints cannot be satisfied",
+			Errors:  reasons,
+		})
+	case errors.Is(err, service.ErrNoStudentsToGroup):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "No students to group",
+			Errors:  []string{err.Error()},
+		})
+	default:
+		logger.Errorf("Failed to form groups for class %s: %v", class.PublicID, err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to form groups",
+			Errors:  []string{err.Error()},
+		})
+	}
+}
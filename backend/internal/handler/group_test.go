@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/pkg/database"
+)
+
+func TestFormClassGroups_InvalidBody(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "X58E9647"})
+	c.Request, _ = http.NewRequest("POST", "/classes/X58E9647/groups", strings.NewReader(`{"groupSize": "five"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.FormClassGroups(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid body, got %d", w.Code)
+	}
+}
+
+func TestGetClassGroups_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("GET", "/classes/nonexistent/groups", nil)
+
+	handler.GetClassGroups(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
+
+func TestGetGroupPairings_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("GET", "/classes/nonexistent/groups/pairings", nil)
+
+	handler.GetGroupPairings(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
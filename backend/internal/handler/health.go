@@ -2,6 +2,7 @@ package handler
 
 import (
 	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
 	"net/http"
 	"time"
 
@@ -15,6 +16,7 @@ func GetHealth(c *gin.Context) {
 		Data: map[string]interface{}{
 			"status":    "healthy",
 			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"websocket": service.GetWebSocketStats(),
 		},
 		Message: "Service is running",
 	})
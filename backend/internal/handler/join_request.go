@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// joinRequestStatus describes a join request to the device following it.
+func joinRequestStatus(request *model.JoinRequest) model.JoinRequestStatus {
+	return model.JoinRequestStatus{
+		RequestID: request.ID,
+		Status:    request.Status,
+		PollURL:   fmt.Sprintf("%s/api/v1/join-requests/%s", config.BaseURL(), request.PollToken),
+	}
+}
+
+// PollJoinRequest handles GET /api/v1/join-requests/:pollToken
+// With ?wait=<seconds> the response is held until the teacher decides or the wait runs out.
+// An approved request carries the student app URL with the student's session token.
+func PollJoinRequest(c *gin.Context) {
+	db := database.GetDB()
+
+	wait := 0
+	if raw := c.Query("wait"); raw != "" {
+		var err error
+		wait, err = strconv.Atoi(raw)
+		if err != nil || wait < 0 {
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid wait",
+				Errors:  []string{"'wait' must be a number of seconds"},
+			})
+			return
+		}
+	}
+
+	request, err := service.WaitForJoinDecision(c.Request.Context(), db, c.Param("pollToken"), time.Duration(wait)*time.Second)
+	if err != nil {
+		respondJoinRequestError(c, err, "Failed to retrieve join request")
+		return
+	}
+
+	status := joinRequestStatus(request)
+	if request.Status == model.JoinRequestApproved {
+		status.RedirectURL, err = service.StudentRedirectURL(config.ClassRedirectionBaseURL(), request.SessionToken)
+		if err != nil {
+			respondJoinRequestError(c, err, "Failed to retrieve join request")
+			return
+		}
+	}
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    status,
+		Message: "Join request retrieved successfully",
+	})
+}
+
+// GetJoinRequests handles GET /api/v1/classes/:classId/join-requests
+func GetJoinRequests(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	requests, err := service.ListJoinRequests(db, class.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve join requests",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    requests,
+		Message: "Join requests retrieved successfully",
+	})
+}
+
+// ApproveJoinRequest handles POST /api/v1/classes/:classId/join-requests/:requestId/approve
+func ApproveJoinRequest(c *gin.Context) {
+	decideJoinRequest(c, true)
+}
+
+// RejectJoinRequest handles POST /api/v1/classes/:classId/join-requests/:requestId/reject
+func RejectJoinRequest(c *gin.Context) {
+	decideJoinRequest(c, false)
+}
+
+// decideJoinRequest approves or rejects the join request named in the path.
+func decideJoinRequest(c *gin.Context, approve bool) {
+	db := database.GetDB()
+
+	requestID, err := strconv.ParseUint(c.Param("requestId"), 10, 64)
+	if err != nil || requestID == 0 {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid join request ID",
+			Errors:  []string{"'requestId' must be a positive integer"},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	request, err := service.DecideJoinRequest(db, class, uint(requestID), approve)
+	if err != nil {
+		respondJoinRequestError(c, err, "Failed to decide join request")
+		return
+	}
+
+	message := "Join request rejected"
+	if approve {
+		message = "Join request approved"
+	}
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    request,
+		Message: message,
+	})
+}
+
+// respondJoinRequestError maps join request service errors to API responses.
+func respondJoinRequestError(c *gin.Context, err error, failureMessage string) {
+	switch {
+	case errors.Is(err, service.ErrJoinRequestDecided), errors.Is(err, service.ErrClassFull),
+		errors.Is(err, service.ErrClassInactive), errors.Is(err, service.ErrClassLocked),
+		errors.As(err, new(*service.WaitlistedError)), errors.Is(err, service.ErrAmbiguousName):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Join request not found",
+			Errors:  []string{"Join request with the specified ID does not exist"},
+		})
+	default:
+		logger.Errorf("%s: %v", failureMessage, err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	}
+}
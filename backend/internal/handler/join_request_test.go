@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/pkg/database"
+)
+
+func TestPollJoinRequest_InvalidWait(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "pollToken", Value: "abc"})
+	c.Request, _ = http.NewRequest("GET", "/join-requests/abc?wait=soon", nil)
+
+	handler.PollJoinRequest(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid wait, got %d", w.Code)
+	}
+}
+
+func TestApproveJoinRequest_InvalidID(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params,
+		gin.Param{Key: "classId", Value: "X58E9647"},
+		gin.Param{Key: "requestId", Value: "0"})
+	c.Request, _ = http.NewRequest("POST", "/classes/X58E9647/join-requests/0/approve", nil)
+
+	handler.ApproveJoinRequest(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid join request ID, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Invalid join request ID") {
+		t.Errorf("Expected invalid join request ID message, got %s", w.Body.String())
+	}
+}
+
+func TestGetJoinRequests_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("GET", "/classes/nonexistent/join-requests", nil)
+
+	handler.GetJoinRequests(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
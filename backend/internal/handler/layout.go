@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// GetSeatingLayout handles GET /api/v1/classes/:classId/layout
+// Classes without a saved layout get their default grid.
+func GetSeatingLayout(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	layout, err := service.GetSeatingLayout(db, class)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve seating layout",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    layout,
+		Message: "Seating layout retrieved successfully",
+	})
+}
+
+// UpdateSeatingLayout handles PUT /api/v1/classes/:classId/layout
+func UpdateSeatingLayout(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.SeatingLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	layout, err := service.SaveSeatingLayout(db, class, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidLayout):
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid seating layout",
+				Errors:  []string{err.Error()},
+			})
+		case errors.Is(err, service.ErrLayoutExcludesEnrolledSeat):
+			c.JSON(http.StatusConflict, model.APIResponse{
+				Success: false,
+				Message: "Seating layout conflicts with enrolled students",
+				Errors:  []string{err.Error()},
+			})
+		default:
+			logger.Errorf("Failed to save seating layout for class %s: %v", class.PublicID, err)
+			c.JSON(http.StatusInternalServerError, model.APIResponse{
+				Success: false,
+				Message: "Failed to save seating layout",
+				Errors:  []string{err.Error()},
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    layout,
+		Message: "Seating layout saved successfully",
+	})
+}
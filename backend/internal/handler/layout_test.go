@@ -0,0 +1,45 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/pkg/database"
+)
+
+func TestUpdateSeatingLayout_InvalidBody(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "X58E9647"})
+	c.Request, _ = http.NewRequest("PUT", "/classes/X58E9647/layout", strings.NewReader(`{"rows": "three"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.UpdateSeatingLayout(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid body, got %d", w.Code)
+	}
+}
+
+func TestGetSeatingLayout_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("GET", "/classes/nonexistent/layout", nil)
+
+	handler.GetSeatingLayout(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// GetPairingConstraints handles GET /api/v1/classes/:classId/pairing-constraints
+func GetPairingConstraints(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	constraints, err := service.ListPairingConstraints(db, class.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve pairing constraints",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    constraints,
+		Message: "Pairing constraints retrieved successfully",
+	})
+}
+
+// AddPairingConstraint handles POST /api/v1/classes/:classId/pairing-constraints
+func AddPairingConstraint(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.PairingConstraintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	constraint, err := service.AddPairingConstraint(db, class.ID, req)
+	if err != nil {
+		respondPairingError(c, err, "Failed to add pairing constraint")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Data:    constraint,
+		Message: "Pairing constraint added successfully",
+	})
+}
+
+// DeletePairingConstraint handles DELETE /api/v1/classes/:classId/pairing-constraints/:constraintId
+func DeletePairingConstraint(c *gin.Context) {
+	db := database.GetDB()
+
+	constraintID, err := strconv.ParseUint(c.Param("constraintId"), 10, 64)
+	if err != nil || constraintID == 0 {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid constraint ID",
+			Errors:  []string{"'constraintId' must be a positive integer"},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	if err := service.DeletePairingConstraint(db, class.ID, uint(constraintID)); err != nil {
+		respondPairingError(c, err, "Failed to delete pairing constraint")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Pairing constraint deleted successfully",
+	})
+}
+
+// respondPairingError maps pairing constraint service errors to API responses.
+func respondPairingError(c *gin.Context, err error, failureMessage string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPairingConstraint):
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrPairingConstraintExists):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrNotEnrolled):
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Student not found",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Pairing constraint not found",
+			Errors:  []string{"Pairing constraint with the specified ID does not exist in this class"},
+		})
+	default:
+		logger.Errorf("%s: %v", failureMessage, err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	}
+}
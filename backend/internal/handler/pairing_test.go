@@ -0,0 +1,50 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/pkg/database"
+)
+
+func TestAddPairingConstraint_InvalidBody(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "X58E9647"})
+	c.Request, _ = http.NewRequest("POST", "/classes/X58E9647/pairing-constraints", strings.NewReader("not json"))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.AddPairingConstraint(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid body, got %d", w.Code)
+	}
+}
+
+func TestDeletePairingConstraint_InvalidID(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params,
+		gin.Param{Key: "classId", Value: "X58E9647"},
+		gin.Param{Key: "constraintId", Value: "abc"})
+	c.Request, _ = http.NewRequest("DELETE", "/classes/X58E9647/pairing-constraints/abc", nil)
+
+	handler.DeletePairingConstraint(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid constraint ID, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Invalid constraint ID") {
+		t.Errorf("Expected invalid constraint ID message, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// GetClassPoints handles GET /api/v1/classes/:classId/points
+// Returns totals for the current session, or for the session given by ?sessionId=.
+func GetClassPoints(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	session, ok := resolveSession(c, db, class)
+	if !ok {
+		return
+	}
+
+	totals, err := service.GetPointTotals(db, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve points",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    totals,
+		Message: "Points retrieved successfully",
+	})
+}
+
+// AwardPoints handles POST /api/v1/classes/:classId/points/award
+func AwardPoints(c *gin.Context) {
+	recordPoints(c, 1, "Points awarded successfully")
+}
+
+// DeductPoints handles POST /api/v1/classes/:classId/points/deduct
+func DeductPoints(c *gin.Context) {
+	recordPoints(c, -1, "Points deducted successfully")
+}
+
+// recordPoints writes a ledger event with the requested points multiplied by sign.
+func recordPoints(c *gin.Context, sign int, successMessage string) {
+	db := database.GetDB()
+
+	var req model.PointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+	if req.Points <= 0 {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid points value",
+			Errors:  []string{"'points' must be a positive number"},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	teacher, ok := currentTeacher(c)
+	if !ok {
+		return
+	}
+
+	event, total, err := service.RecordSessionPoints(db, class, req, teacher.Email, sign)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoActiveSession):
+			respondSessionError(c, err)
+		case errors.Is(err, service.ErrInvalidPointTarget):
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid point target",
+				Errors:  []string{err.Error()},
+			})
+		case errors.Is(err, service.ErrInsufficientPoints):
+			c.JSON(http.StatusConflict, model.APIResponse{
+				Success: false,
+				Message: "Insufficient points",
+				Errors:  []string{err.Error()},
+			})
+		default:
+			logger.Errorf("Failed to record points for class %s: %v", class.PublicID, err)
+			c.JSON(http.StatusInternalServerError, model.APIResponse{
+				Success: false,
+				Message: "Failed to record points",
+				Errors:  []string{err.Error()},
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    model.PointUpdateResponse{Event: *event, Total: total},
+		Message: successMessage,
+	})
+}
@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/pkg/database"
+)
+
+func TestAwardPoints_InvalidBody(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "X58E9647"})
+	c.Request, _ = http.NewRequest("POST", "/classes/X58E9647/points/award", strings.NewReader("not json"))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.AwardPoints(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid body, got %d", w.Code)
+	}
+}
+
+func TestDeductPoints_NonPositivePoints(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "X58E9647"})
+	c.Request, _ = http.NewRequest("POST", "/classes/X58E9647/points/deduct", strings.NewReader(`{"studentId": 1, "points": 0}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.DeductPoints(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for non-positive points, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Invalid points value") {
+		t.Errorf("Expected invalid points message, got %s", w.Body.String())
+	}
+}
+
+func TestGetClassPoints_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("GET", "/classes/nonexistent/points", nil)
+
+	handler.GetClassPoints(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
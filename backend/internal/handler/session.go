@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// GetClassSessions handles GET /api/v1/classes/:classId/sessions
+func GetClassSessions(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	sessions, err := service.ListSessions(db, class.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to retrieve sessions",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    sessions,
+		Message: "Sessions retrieved successfully",
+	})
+}
+
+// GetClassSession handles GET /api/v1/classes/:classId/sessions/:sessionId
+func GetClassSession(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("sessionId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid session ID",
+			Errors:  []string{"'sessionId' must be a positive integer"},
+		})
+		return
+	}
+
+	session, err := service.GetSession(db, class.ID, uint(sessionID))
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    session,
+		Message: "Session retrieved successfully",
+	})
+}
+
+// GetCurrentClassSession handles GET /api/v1/classes/:classId/sessions/current
+func GetCurrentClassSession(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	session, err := service.GetCurrentSession(db, class.ID)
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    session,
+		Message: "Session retrieved successfully",
+	})
+}
+
+// StartClassSession handles POST /api/v1/classes/:classId/sessions
+func StartClassSession(c *gin.Context) {
+	changeSession(c, service.StartSession, http.StatusCreated, sessionChanged(model.EventSessionStarted), "Session started successfully")
+}
+
+// PauseClassSession handles POST /api/v1/classes/:classId/sessions/current/pause
+func PauseClassSession(c *gin.Context) {
+	changeSession(c, service.PauseSession, http.StatusOK, sessionChanged(model.EventSessionPaused), "Session paused successfully")
+}
+
+// ResumeClassSession handles POST /api/v1/classes/:classId/sessions/current/resume
+func ResumeClassSession(c *gin.Context) {
+	changeSession(c, service.ResumeSession, http.StatusOK, sessionChanged(model.EventSessionResumed), "Session resumed successfully")
+}
+
+// EndClassSession handles POST /api/v1/classes/:classId/sessions/current/end
+func EndClassSession(c *gin.Context) {
+	changeSession(c, service.EndSession, http.StatusOK, sessionChanged(model.EventSessionEnded), "Session ended successfully")
+}
+
+// LockClassJoins handles POST /api/v1/classes/:classId/sessions/current/lock
+func LockClassJoins(c *gin.Context) {
+	changeSession(c, service.LockJoins, http.StatusOK, joinLockChanged, "Joining locked successfully")
+}
+
+// UnlockClassJoins handles POST /api/v1/classes/:classId/sessions/current/unlock
+// Unlocking also cancels the session's automatic lock.
+func UnlockClassJoins(c *gin.Context) {
+	changeSession(c, service.UnlockJoins, http.StatusOK, joinLockChanged, "Joining unlocked successfully")
+}
+
+// sessionChanged returns the event reporting a session lifecycle transition.
+func sessionChanged(eventType string) func(session *model.ClassSession) model.Event {
+	return func(session *model.ClassSession) model.Event {
+		return model.SessionChangedEvent{Type: eventType, Session: *session}
+	}
+}
+
+// joinLockChanged returns the event reporting a session's join lock.
+func joinLockChanged(session *model.ClassSession) model.Event {
+	return model.JoinLockEvent{Session: *session}
+}
+
+// changeSession applies a change to the current session and notifies the class dashboards.
+func changeSession(
+	c *gin.Context,
+	change func(db *gorm.DB, classID string) (*model.ClassSession, error),
+	status int,
+	event func(session *model.ClassSession) model.Event,
+	successMessage string,
+) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	session, err := change(db, class.ID)
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	service.BroadcastEvent(class.PublicID, event(session))
+
+	c.JSON(status, model.APIResponse{
+		Success: true,
+		Data:    session,
+		Message: successMessage,
+	})
+}
+
+// respondSessionError maps session service errors to API responses.
+func respondSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNoActiveSession):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "No active session",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrSessionAlreadyActive), errors.Is(err, service.ErrInvalidSessionTransition):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Session state conflict",
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Session not found",
+			Errors:  []string{"Session with the specified ID does not exist"},
+		})
+	default:
+		logger.Errorf("Session operation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to process session",
+			Errors:  []string{err.Error()},
+		})
+	}
+}
+
+// resolveSession returns the session named by the sessionId query parameter, or the
+// class's current session when it is absent. Writes the error response on failure.
+func resolveSession(c *gin.Context, db *gorm.DB, class *model.Class) (*model.ClassSession, bool) {
+	var (
+		session *model.ClassSession
+		err     error
+	)
+	if raw := c.Query("sessionId"); raw != "" {
+		sessionID, parseErr := strconv.ParseUint(raw, 10, 64)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid session ID",
+				Errors:  []string{"'sessionId' must be a positive integer"},
+			})
+			return nil, false
+		}
+		session, err = service.GetSession(db, class.ID, uint(sessionID))
+	} else {
+		session, err = service.GetCurrentSession(db, class.ID)
+	}
+	if err != nil {
+		respondSessionError(c, err)
+		return nil, false
+	}
+	return session, true
+}
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// maxRosterUploadBytes bounds the size of a roster CSV upload.
+const maxRosterUploadBytes = 1 << 20
+
+// CreateStudent handles POST /api/v1/students
+// The new student belongs to the authenticated teacher.
+func CreateStudent(c *gin.Context) {
+	db := database.GetDB()
+	teacherID, ok := currentTeacherID(c)
+	if !ok {
+		return
+	}
+
+	var req model.StudentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	student := &model.Student{Name: req.Name, OwnerID: &teacherID}
+	if err := service.CreateStudent(db, student); err != nil {
+		respondStudentError(c, err, "Failed to create student")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Data:    student,
+		Message: "Student created successfully",
+	})
+}
+
+// RenameStudent handles PATCH /api/v1/students/:studentId
+func RenameStudent(c *gin.Context) {
+	db := database.GetDB()
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req model.StudentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	student, err := service.RenameStudent(db, studentID, req.Name)
+	if err != nil {
+		respondStudentError(c, err, "Failed to rename student")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    student,
+		Message: "Student renamed successfully",
+	})
+}
+
+// DeleteStudent handles DELETE /api/v1/students/:studentId
+func DeleteStudent(c *gin.Context) {
+	db := database.GetDB()
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	if err := service.DeleteStudent(db, studentID); err != nil {
+		respondStudentError(c, err, "Failed to delete student")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Student deleted successfully",
+	})
+}
+
+// GetClassStudents handles GET /api/v1/classes/:classId/students
+func GetClassStudents(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	students, err := service.GetClassRoster(db, class.ID)
+	if err != nil {
+		respondStudentError(c, err, "Failed to retrieve students")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    students,
+		Message: "Students retrieved successfully",
+	})
+}
+
+// EnrollStudent handles POST /api/v1/classes/:classId/students
+// Only the teacher the student belongs to may enroll them.
+func EnrollStudent(c *gin.Context) {
+	db := database.GetDB()
+	teacherID, ok := currentTeacherID(c)
+	if !ok {
+		return
+	}
+
+	var req model.EnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	if _, err := service.GetStudent(db, req.StudentID); err != nil {
+		respondStudentError(c, err, "Failed to enroll student")
+		return
+	}
+	manages, err := service.ManagesStudent(db, req.StudentID, teacherID)
+	if err != nil {
+		respondStudentError(c, err, "Failed to enroll student")
+		return
+	}
+	if !manages {
+		c.JSON(http.StatusForbidden, model.APIResponse{
+			Success: false,
+			Message: "Student belongs to another teacher",
+		})
+		return
+	}
+
+	preferredSeat, err := service.EnrollStudent(db, class, req.StudentID, req.SeatNumber)
+	if err != nil {
+		respondStudentError(c, err, "Failed to enroll student")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Data:    preferredSeat,
+		Message: "Student enrolled successfully",
+	})
+}
+
+// PromoteGuest handles POST /api/v1/classes/:classId/students/promote
+// Enrolls the guest in seatNumber of the current session as a new student, keeping their attendance and points.
+func PromoteGuest(c *gin.Context) {
+	db := database.GetDB()
+
+	var req model.GuestPromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+	if req.SeatNumber <= 0 {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Errors:  []string{"'seatNumber' must be the positive seat number of a guest"},
+		})
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	enrolled, err := service.PromoteGuest(db, class, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoActiveSession):
+			respondSessionError(c, err)
+		case errors.Is(err, service.ErrNotInSession):
+			c.JSON(http.StatusNotFound, model.APIResponse{
+				Success: false,
+				Message: "No guest in this seat",
+				Errors:  []string{err.Error()},
+			})
+		default:
+			respondStudentError(c, err, "Failed to promote guest")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Data:    enrolled,
+		Message: "Guest enrolled successfully",
+	})
+}
+
+// UnenrollStudent handles DELETE /api/v1/classes/:classId/students/:studentId
+func UnenrollStudent(c *gin.Context) {
+	db := database.GetDB()
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	if err := service.UnenrollStudent(db, class.ID, studentID); err != nil {
+		respondStudentError(c, err, "Failed to unenroll student")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Student unenrolled successfully",
+	})
+}
+
+// ImportClassStudents handles POST /api/v1/classes/:classId/students/import
+// Accepts a CSV of "name,seat" rows, either as a multipart "file" field or as the raw request body.
+func ImportClassStudents(c *gin.Context) {
+	db := database.GetDB()
+
+	class, err := service.GetClassByPublicID(db, c.Param("classId"))
+	if err != nil {
+		respondClassNotFound(c)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRosterUploadBytes)
+	var upload io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, _, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid CSV upload",
+				Errors:  []string{"Missing 'file' field in multipart upload"},
+			})
+			return
+		}
+		defer file.Close()
+		upload = file
+	}
+
+	rows, rowErrors, err := service.ParseRosterCSV(upload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid CSV upload",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+
+	result := service.ImportRoster(db, class, rows)
+	result.Errors = append(rowErrors, result.Errors...)
+
+	message := "Roster imported successfully"
+	if len(result.Errors) > 0 {
+		message = "Roster imported with errors"
+	}
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: len(result.Errors) == 0,
+		Data:    result,
+		Message: message,
+	})
+}
+
+// parseStudentID reads the :studentId path parameter. Writes a 400 response if it is invalid.
+func parseStudentID(c *gin.Context) (uint, bool) {
+	studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 64)
+	if err != nil || studentID == 0 {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid student ID",
+			Errors:  []string{"'studentId' must be a positive integer"},
+		})
+		return 0, false
+	}
+	return uint(studentID), true
+}
+
+// respondStudentError maps roster service errors to API responses.
+func respondStudentError(c *gin.Context, err error, failureMessage string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidStudentName), errors.Is(err, service.ErrInvalidSeatNumber):
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, service.ErrSeatTaken), errors.Is(err, service.ErrAlreadyEnrolled), errors.Is(err, service.ErrClassFull):
+		c.JSON(http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, service.ErrNotEnrolled):
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Student not found",
+			Errors:  []string{err.Error()},
+		})
+	default:
+		logger.Errorf("%s: %v", failureMessage, err)
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: failureMessage,
+			Errors:  []string{err.Error()},
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/internal/middleware"
+	"classswift-backend/pkg/database"
+)
+
+func TestRenameStudent_InvalidID(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "studentId", Value: "abc"})
+	c.Request, _ = http.NewRequest("PATCH", "/students/abc", strings.NewReader(`{"name": "James"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.RenameStudent(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid student ID, got %d", w.Code)
+	}
+}
+
+func TestCreateStudent_EmptyName(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/students", middleware.RequireTeacher(), handler.CreateStudent)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/students", strings.NewReader(`{"name": "  "}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+teacherToken(t, 1))
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty name, got %d", w.Code)
+	}
+}
+
+func TestImportClassStudents_NotFound(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "nonexistent"})
+	c.Request, _ = http.NewRequest("POST", "/classes/nonexistent/students/import", strings.NewReader("name,seat\nPhilip,1\n"))
+	c.Request.Header.Set("Content-Type", "text/csv")
+
+	handler.ImportClassStudents(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing class, got %d", w.Code)
+	}
+}
+
+func TestPromoteGuest_MissingSeat(t *testing.T) {
+	config.Init()
+	database.SetDB(setupMockDB(t))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = append(c.Params, gin.Param{Key: "classId", Value: "PUB1"})
+	c.Request, _ = http.NewRequest("POST", "/classes/PUB1/students/promote", strings.NewReader(`{"name": "Nora Lee"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.PromoteGuest(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for missing seat number, got %d", w.Code)
+	}
+}
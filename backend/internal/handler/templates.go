@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"embed"
+	"html/template"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/pkg/logger"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// pageTemplates holds the server-rendered pages served to student devices.
+var pageTemplates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// renderPage writes the named HTML template with the given status code.
+func renderPage(c *gin.Context, status int, name string, data interface{}) {
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplates.ExecuteTemplate(c.Writer, name, data); err != nil {
+		logger.Errorf("Failed to render %s: %v", name, err)
+	}
+}
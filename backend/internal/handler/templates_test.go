@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/internal/model"
+)
+
+func TestRenderJoinPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	class := &model.Class{PublicID: "X58E9647", Name: "302 Science", StudentCount: 30, TotalCapacity: 30, IsActive: true}
+	page := newJoinPageData(class, 30)
+	page.Error = "Please enter your name to join the class."
+
+	renderPage(c, http.StatusBadRequest, "join.html", page)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"302 Science", "X58E9647", "30 / 30 seats taken", "class is full", page.Error, `<form method="post">`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected page to contain %q", want)
+		}
+	}
+}
+
+func TestRenderJoinPage_InactiveClass(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	class := &model.Class{PublicID: "A12B3456", Name: "101 Math", StudentCount: 30, TotalCapacity: 30, IsActive: false}
+	renderPage(c, http.StatusOK, "join.html", newJoinPageData(class, 3))
+
+	body := w.Body.String()
+	if !strings.Contains(body, "not accepting students") {
+		t.Error("Expected inactive notice on join page")
+	}
+	if !strings.Contains(body, "3 / 30 seats taken") {
+		t.Error("Expected seats taken to count session attendance, not enrollment")
+	}
+	if strings.Contains(body, "class is full") {
+		t.Error("Did not expect class to be reported as full")
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+	"classswift-backend/pkg/logger"
+)
+
+// VerifyStudentToken handles POST /api/v1/tokens/verify
+// The token may be sent in the JSON body or as an "Authorization: Bearer" header.
+// Tokens of students who have left or been removed from their session are no longer valid.
+func VerifyStudentToken(c *gin.Context) {
+	claims, ok := bindStudentToken(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    claims,
+		Message: "Session token is valid",
+	})
+}
+
+// bindStudentToken reads the student session token from the "Authorization: Bearer" header or
+// the JSON body and verifies it, responding with the error if it is missing or no longer valid.
+func bindStudentToken(c *gin.Context) (*model.StudentSessionClaims, bool) {
+	signed, hasBearer := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !hasBearer {
+		var req model.TokenVerifyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Token is required",
+				Errors:  []string{err.Error()},
+			})
+			return nil, false
+		}
+		signed = req.Token
+	}
+
+	claims, err := service.VerifyStudentToken(signed)
+	if err == nil {
+		err = service.CheckStudentSession(database.GetDB(), claims)
+	}
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalidToken) {
+			logger.Errorf("Failed to check student session token: %v", err)
+			c.JSON(http.StatusInternalServerError, model.APIResponse{
+				Success: false,
+				Message: "Failed to verify session token",
+				Errors:  []string{err.Error()},
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "Invalid or expired session token",
+		})
+		return nil, false
+	}
+	return claims, true
+}
@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/handler"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestVerifyStudentToken_MissingToken(t *testing.T) {
+	config.Init()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/tokens/verify", strings.NewReader(`{}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.VerifyStudentToken(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for missing token, got %d", w.Code)
+	}
+}
+
+func TestVerifyStudentToken_Invalid(t *testing.T) {
+	config.Init()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/tokens/verify", strings.NewReader(`{"token":"not.valid"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.VerifyStudentToken(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for invalid token, got %d", w.Code)
+	}
+}
+
+func TestVerifyStudentToken_BearerHeader(t *testing.T) {
+	config.Init()
+	signed, _, err := service.IssueStudentToken(&model.JoinResult{Class: &model.Class{PublicID: "PUB1"}}, "Guest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/tokens/verify", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+signed)
+
+	handler.VerifyStudentToken(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for valid token, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"classId":"PUB1"`) {
+		t.Errorf("Expected claims in response, got %s", w.Body.String())
+	}
+}
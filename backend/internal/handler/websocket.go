@@ -3,16 +3,24 @@ package handler
 import (
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 
+	"classswift-backend/internal/middleware"
 	"classswift-backend/internal/model"
 	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
 	"classswift-backend/pkg/logger"
 )
 
+// maxClientMessageSize bounds a message read from a client. The largest valid command is a points command with
+// a 255-character reason (the size of the point_events column), which JSON-escaping can take to 12 bytes a character;
+// the rest leaves room for the envelope, the request ID and pretty-printed JSON.
+const maxClientMessageSize = 4 << 10
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		// Allow connections from any origin for development
@@ -21,7 +29,15 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// GetWebSocketSchema handles GET /api/v1/ws/schema
+// Returns the JSON Schema of the WebSocket message protocol.
+func GetWebSocketSchema(c *gin.Context) {
+	c.JSON(http.StatusOK, service.WebSocketProtocolSchema())
+}
+
 // HandleWebSocket handles WebSocket connection requests
+// A new connection first receives a class_state snapshot. With ?since=<seq>, the class messages published
+// after seq are replayed instead, or a resync_required message is sent if they are no longer buffered.
 func HandleWebSocket(c *gin.Context) {
 	classID := c.Param("classId")
 	if classID == "" {
@@ -33,16 +49,56 @@ func HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Commands sent over the connection are authorized as the teacher who opened it
+	client := &model.Client{ClassID: classID}
+	if claims, ok := middleware.CurrentTeacher(c); ok {
+		client.TeacherID = claims.TeacherID
+		client.TeacherEmail = claims.Email
+	}
+
+	// A reconnecting client passes ?since=<seq> with the last sequence number it received
+	if since := c.Query("since"); since != "" {
+		seq, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid since parameter",
+				Errors:  []string{"since must be a non-negative sequence number"},
+			})
+			return
+		}
+		client.Resume = true
+		client.Since = seq
+	} else {
+		db := database.GetDB()
+		class, err := service.GetClassByPublicID(db, classID)
+		if err != nil {
+			respondClassNotFound(c)
+			return
+		}
+		state, err := service.GetClassState(db, class)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, model.APIResponse{
+				Success: false,
+				Message: "Failed to retrieve class state",
+				Errors:  []string{err.Error()},
+			})
+			return
+		}
+
+		// Messages delivered after the snapshot was taken follow it
+		initial := model.NewWebSocketMessage(classID, *state)
+		client.Initial = &initial
+		client.Resume = true
+		client.Since = state.Seq
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		logger.Errorf("Failed to upgrade WebSocket connection: %v", err)
 		return
 	}
-
-	client := &model.Client{
-		Conn:    conn,
-		ClassID: classID,
-	}
+	client.Conn = conn
 
 	service.RegisterClient(client)
 
@@ -51,8 +107,8 @@ func HandleWebSocket(c *gin.Context) {
 		service.UnregisterClient(client)
 	}()
 
-	// Keep connection alive and handle ping/pong
-	conn.SetReadLimit(512)
+	// Keep connection alive and handle pongs; pings are sent by the hub's writer goroutine for this client
+	conn.SetReadLimit(maxClientMessageSize)
 	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	conn.SetPongHandler(func(string) error {
 		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -60,20 +116,6 @@ func HandleWebSocket(c *gin.Context) {
 		return nil
 	})
 
-	// Start heartbeat goroutine
-	go func() {
-		ticker := time.NewTicker(30 * time.Second) // Send ping every 30 seconds
-		defer ticker.Stop()
-
-		for range ticker.C {
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				logger.Errorf("Failed to send ping to client in class %s: %v", classID, err)
-				return
-			}
-			logger.Infof("Sent ping to client in class %s", classID)
-		}
-	}()
-
 	// Read messages from client (mainly for keep-alive and message handling)
 	for {
 		messageType, message, err := conn.ReadMessage()
@@ -87,10 +129,8 @@ func HandleWebSocket(c *gin.Context) {
 		// Handle different message types
 		switch messageType {
 		case websocket.TextMessage:
-			messageStr := string(message)
-			logger.Infof("Received text message from client in class %s: %s", classID, messageStr)
-			// You can add message processing logic here
-			
+			service.HandleClientMessage(database.GetDB(), client, message)
+
 		case websocket.PongMessage:
 			logger.Infof("Received pong frame from client in class %s", classID)
 		}
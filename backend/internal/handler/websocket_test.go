@@ -5,17 +5,35 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 
 	"classswift-backend/internal/model"
 	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
 )
 
+// newMockDB returns a DB with no expected queries, so class lookups fail as not found
+func newMockDB(t *testing.T) *gorm.DB {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock database: %v", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm DB: %v", err)
+	}
+	return gormDB
+}
+
 func TestHandleWebSocket_MissingClassID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	
+
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
@@ -37,9 +55,10 @@ func TestHandleWebSocket_MissingClassID(t *testing.T) {
 
 func TestHandleWebSocket_WithClassID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	
+
 	// Initialize WebSocket hub for testing
 	service.InitWebSocketHub()
+	database.SetDB(newMockDB(t))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -62,11 +81,29 @@ func TestHandleWebSocket_WithClassID(t *testing.T) {
 	// but we can verify the function doesn't panic with a valid classId
 }
 
+func TestHandleWebSocket_InvalidSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/classes/:classId/ws", HandleWebSocket)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/classes/valid-class/ws?since=abc", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Invalid since parameter") {
+		t.Errorf("Expected invalid since message, got %s", w.Body.String())
+	}
+}
+
 func TestUpgraderOriginCheck(t *testing.T) {
 	// Test that our upgrader allows all origins (for development)
 	req := httptest.NewRequest("GET", "/ws", nil)
 	req.Header.Set("Origin", "http://localhost:3000")
-	
+
 	allowed := upgrader.CheckOrigin(req)
 	if !allowed {
 		t.Error("Expected upgrader to allow all origins for development")
@@ -82,9 +119,10 @@ func TestUpgraderOriginCheck(t *testing.T) {
 
 func TestWebSocketHandlerIntegration(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	
+
 	// Initialize WebSocket service
 	service.InitWebSocketHub()
+	database.SetDB(newMockDB(t))
 
 	// Create a test router
 	r := gin.New()
@@ -140,4 +178,49 @@ func TestWebSocketClient(t *testing.T) {
 	if client.Conn != nil {
 		t.Error("Expected Conn to be nil initially")
 	}
-}
\ No newline at end of file
+}
+
+func TestHandleWebSocket_FullSizeCommand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service.InitWebSocketHub()
+	database.SetDB(newMockDB(t))
+
+	r := gin.New()
+	r.GET("/classes/:classId/ws", HandleWebSocket)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/classes/PUB1/ws?since=0", nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// A pretty-printed regroup command padded out to the read limit with its request ID
+	prefix := "{\n  \"version\": 1,\n  \"type\": \"regroup\",\n  \"requestId\": \""
+	suffix := "\",\n  \"data\": {\n    \"strategy\": \"random\",\n    \"groupCount\": 4,\n    \"presentOnly\": true\n  }\n}"
+	requestID := strings.Repeat("r", maxClientMessageSize-len(prefix)-len(suffix))
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(prefix+requestID+suffix)); err != nil {
+		t.Fatalf("failed to send command: %v", err)
+	}
+
+	// The command is read and answered, whatever its outcome, rather than closing the connection
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var message struct {
+			Type string `json:"type"`
+			Data struct {
+				RequestID string `json:"requestId"`
+			} `json:"data"`
+		}
+		if err := conn.ReadJSON(&message); err != nil {
+			t.Fatalf("expected a reply to the command, got %v", err)
+		}
+		if message.Type == model.EventAck || message.Type == model.EventError {
+			if message.Data.RequestID != requestID {
+				t.Errorf("expected a reply to the full-size command, got a %s for another request", message.Type)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+)
+
+// teacherContextKey is the gin context key holding the authenticated teacher's claims.
+const teacherContextKey = "teacher"
+
+// RequireTeacher returns a Gin middleware that rejects requests without a valid teacher access token.
+// The token is read from the "Authorization: Bearer" header, or from the "token" query parameter
+// for WebSocket upgrades where browsers cannot set headers.
+func RequireTeacher() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := authenticateTeacher(c); !ok {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireClassOwner returns a Gin middleware that only lets the teacher owning the :classId class through.
+func RequireClassOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := authenticateTeacher(c)
+		if !ok {
+			return
+		}
+
+		class, err := service.GetClassByPublicID(database.GetDB(), c.Param("classId"))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.AbortWithStatusJSON(http.StatusNotFound, model.APIResponse{
+					Success: false,
+					Message: "Class not found",
+					Errors:  []string{"Class with the specified ID does not exist"},
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, model.APIResponse{
+				Success: false,
+				Message: "Failed to load class",
+				Errors:  []string{err.Error()},
+			})
+			return
+		}
+
+		if !service.IsClassOwner(class, claims.TeacherID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, model.APIResponse{
+				Success: false,
+				Message: "You do not have access to this class",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireStudentOwner returns a Gin middleware that only lets a teacher through to the :studentId student
+// when the student belongs to them (see service.ManagesStudent). An unparsable ID is left to the handler.
+func RequireStudentOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := authenticateTeacher(c)
+		if !ok {
+			return
+		}
+
+		studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 64)
+		if err != nil {
+			c.Next()
+			return
+		}
+		manages, err := service.ManagesStudent(database.GetDB(), uint(studentID), claims.TeacherID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, model.APIResponse{
+				Success: false,
+				Message: "Failed to load student",
+				Errors:  []string{err.Error()},
+			})
+			return
+		}
+		if !manages {
+			c.AbortWithStatusJSON(http.StatusForbidden, model.APIResponse{
+				Success: false,
+				Message: "Student belongs to another teacher",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentTeacher returns the claims of the teacher authenticated by RequireTeacher, RequireClassOwner or RequireStudentOwner.
+func CurrentTeacher(c *gin.Context) (*model.TeacherClaims, bool) {
+	value, exists := c.Get(teacherContextKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*model.TeacherClaims)
+	return claims, ok
+}
+
+// authenticateTeacher verifies the request's teacher token and stores its claims on the context.
+// It aborts the request with 401 and returns false when the token is missing or invalid.
+func authenticateTeacher(c *gin.Context) (*model.TeacherClaims, bool) {
+	signed, hasBearer := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !hasBearer {
+		signed = c.Query("token")
+	}
+	if signed == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return nil, false
+	}
+
+	claims, err := service.VerifyTeacherToken(signed)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "Invalid or expired access token",
+		})
+		return nil, false
+	}
+
+	c.Set(teacherContextKey, claims)
+	return claims, true
+}
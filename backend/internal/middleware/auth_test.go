@@ -0,0 +1,195 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/middleware"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+	"classswift-backend/pkg/database"
+)
+
+var (
+	mockOnce sync.Once
+	mockDB   sqlmock.Sqlmock
+)
+
+// setupMockDB installs a sqlmock database shared by the tests of the package,
+// since the global DB can only be set once.
+func setupMockDB(t *testing.T) sqlmock.Sqlmock {
+	mockOnce.Do(func() {
+		sqlDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("failed to open sqlmock database: %v", err)
+		}
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+		if err != nil {
+			t.Fatalf("failed to open gorm DB: %v", err)
+		}
+		database.SetDB(gormDB)
+		mockDB = mock
+	})
+	return mockDB
+}
+
+func setupAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", middleware.RequireTeacher(), func(c *gin.Context) {
+		claims, ok := middleware.CurrentTeacher(c)
+		if !ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, claims)
+	})
+	return r
+}
+
+func TestRequireTeacher(t *testing.T) {
+	config.Init()
+	r := setupAuthRouter()
+
+	teacherToken, _, err := service.IssueTeacherToken(&model.Teacher{ID: 3, Email: "teacher@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	studentToken, _, err := service.IssueStudentToken(&model.JoinResult{Class: &model.Class{PublicID: "PUB1"}}, "Guest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{"missing token", "/me", "", http.StatusUnauthorized},
+		{"bearer header", "/me", "Bearer " + teacherToken, http.StatusOK},
+		{"query parameter", "/me?token=" + teacherToken, "", http.StatusOK},
+		{"student token", "/me", "Bearer " + studentToken, http.StatusUnauthorized},
+		{"garbage token", "/me", "Bearer not.valid", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, w.Code)
+		}
+	}
+}
+
+func TestRequireClassOwner(t *testing.T) {
+	config.Init()
+	mock := setupMockDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/classes/:classId", middleware.RequireClassOwner(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "owner_id"}).AddRow("class-1", "PUB1", "Test Class", 3))
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "owner_id"}).AddRow("class-1", "PUB1", "Test Class", 3))
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("MISSING", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	owner, _, _ := service.IssueTeacherToken(&model.Teacher{ID: 3})
+	other, _, _ := service.IssueTeacherToken(&model.Teacher{ID: 4})
+
+	cases := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"owner", "/classes/PUB1", owner, http.StatusOK},
+		{"other teacher", "/classes/PUB1", other, http.StatusForbidden},
+		{"missing class", "/classes/MISSING", owner, http.StatusNotFound},
+		{"unauthenticated", "/classes/PUB1", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, w.Code)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRequireStudentOwner(t *testing.T) {
+	config.Init()
+	mock := setupMockDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.DELETE("/students/:studentId", middleware.RequireStudentOwner(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	mock.ExpectQuery(`SELECT "id","owner_id" FROM "students" WHERE id = \$1`).
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id"}).AddRow(5, 3))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FILTER \(WHERE c\.owner_id = \$1\) AS own, COUNT\(\*\) FILTER \(WHERE c\.owner_id IS NULL OR c\.owner_id <> \$2\) AS others FROM student_preferred_seats AS sps JOIN classes c ON c\.id = sps\.class_id WHERE sps\.student_id = \$3`).
+		WithArgs(uint(3), uint(3), uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"own", "others"}).AddRow(1, 0))
+	mock.ExpectQuery(`SELECT "id","owner_id" FROM "students" WHERE id = \$1`).
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id"}).AddRow(5, 3))
+
+	owner, _, _ := service.IssueTeacherToken(&model.Teacher{ID: 3})
+	other, _, _ := service.IssueTeacherToken(&model.Teacher{ID: 4})
+
+	cases := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"owner of the student", owner, http.StatusOK},
+		{"another teacher's student", other, http.StatusForbidden},
+		{"unauthenticated", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/students/5", nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, w.Code)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -22,7 +22,7 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = origins
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Student-Name"}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Student-Name", "X-Student-ID"}
 	return cors.New(corsConfig)
 }
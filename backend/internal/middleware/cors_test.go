@@ -4,6 +4,7 @@ import (
 	"classswift-backend/internal/middleware"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -25,3 +26,23 @@ func TestCORSMiddleware(t *testing.T) {
 		t.Error("CORS header not set")
 	}
 }
+
+func TestCORSMiddleware_JoinHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.CORSMiddleware())
+	r.GET("/", func(c *gin.Context) { c.Status(200) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("OPTIONS", "/", nil)
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", "X-Student-Name, X-Student-ID")
+
+	r.ServeHTTP(w, req)
+
+	allowed := strings.ToLower(w.Header().Get("Access-Control-Allow-Headers"))
+	if !strings.Contains(allowed, "x-student-name") || !strings.Contains(allowed, "x-student-id") {
+		t.Errorf("expected the join headers to be allowed, got %q", allowed)
+	}
+}
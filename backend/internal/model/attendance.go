@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// AttendanceRecord records a student or guest joining a class session.
+// NameKey is the normalized name guests are told apart by; the service sets it whenever the name changes.
+// LeftAt and LeftReason are set once they leave or are removed; the record is kept, and the seat freed.
+type AttendanceRecord struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	SessionID  uint       `json:"sessionId" gorm:"not null;index"`
+	ClassID    string     `json:"classId" gorm:"not null;index"`
+	StudentID  *uint      `json:"studentId,omitempty"`
+	Name       string     `json:"name" gorm:"not null"`
+	NameKey    string     `json:"-" gorm:"not null"`
+	SeatNumber int        `json:"seatNumber"`
+	JoinedAt   time.Time  `json:"joinedAt"`
+	IsLate     bool       `json:"isLate"`
+	LeftAt     *time.Time `json:"leftAt,omitempty"`
+	LeftReason string     `json:"leftReason,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// TableName sets the table name for the AttendanceRecord model
+func (AttendanceRecord) TableName() string {
+	return "attendance_records"
+}
+
+// AttendanceReport summarizes who attended a class session.
+// Present includes late joiners and those who have since left; Absent lists enrolled students who never joined.
+type AttendanceReport struct {
+	SessionID uint                            `json:"sessionId"`
+	Present   []AttendanceRecord              `json:"present"`
+	Late      []AttendanceRecord              `json:"late"`
+	Absent    []StudentWithClassPreferredSeat `json:"absent"`
+}
+
+// JoinResult describes the outcome of a student joining a class.
+// Name is who joined: the enrolled student's name, or the guest's name as typed, cleaned up.
+type JoinResult struct {
+	Class         *Class
+	Name          string
+	Student       *Student
+	PreferredSeat *StudentPreferredSeat
+	// Attendance is nil when the class has no open session to record the join against.
+	Attendance *AttendanceRecord
+}
@@ -3,13 +3,41 @@ package model
 import "time"
 
 // Class represents a classroom.
+// Joins to a class session are limited to TotalCapacity; with WaitlistWhenFull, joiners beyond it
+// are put on a waiting list instead of being turned away. With RequireApproval, joins wait for the teacher
+// to approve them. A positive AutoLockMinutes locks joining that many minutes after a session starts.
 type Class struct {
-	ID            string    `json:"id" gorm:"primaryKey"`
-	PublicID      string    `json:"publicId" gorm:"uniqueIndex;not null"`
-	Name          string    `json:"name" gorm:"not null"`
-	StudentCount  int       `json:"studentCount" gorm:"default:0"`
-	TotalCapacity int       `json:"totalCapacity" gorm:"default:30"`
-	IsActive      bool      `json:"isActive" gorm:"default:true"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID               string     `json:"id" gorm:"primaryKey"`
+	PublicID         string     `json:"publicId" gorm:"uniqueIndex;not null"`
+	Name             string     `json:"name" gorm:"not null"`
+	StudentCount     int        `json:"studentCount" gorm:"default:0"`
+	TotalCapacity    int        `json:"totalCapacity" gorm:"default:30"`
+	IsActive         bool       `json:"isActive" gorm:"default:true"`
+	WaitlistWhenFull bool       `json:"waitlistWhenFull" gorm:"not null;default:false"`
+	RequireApproval  bool       `json:"requireApproval" gorm:"not null;default:false"`
+	AutoLockMinutes  int        `json:"autoLockMinutes" gorm:"not null;default:0"`
+	OwnerID          *uint      `json:"ownerId,omitempty"`
+	ArchivedAt       *time.Time `json:"archivedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+// CreateClassRequest is the request body for creating a class.
+type CreateClassRequest struct {
+	Name             string `json:"name"`
+	TotalCapacity    *int   `json:"totalCapacity"`
+	IsActive         *bool  `json:"isActive"`
+	WaitlistWhenFull *bool  `json:"waitlistWhenFull"`
+	RequireApproval  *bool  `json:"requireApproval"`
+	AutoLockMinutes  *int   `json:"autoLockMinutes"`
+}
+
+// UpdateClassRequest is the request body for updating a class. Omitted fields are left unchanged.
+type UpdateClassRequest struct {
+	Name             *string `json:"name"`
+	TotalCapacity    *int    `json:"totalCapacity"`
+	IsActive         *bool   `json:"isActive"`
+	WaitlistWhenFull *bool   `json:"waitlistWhenFull"`
+	RequireApproval  *bool   `json:"requireApproval"`
+	AutoLockMinutes  *int    `json:"autoLockMinutes"`
 }
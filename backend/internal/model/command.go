@@ -0,0 +1,63 @@
+package model
+
+import "errors"
+
+// PingPayload is the data of a ping message; the server answers with a pong carrying the request ID.
+type PingPayload struct{}
+
+// Validate implements ClientPayload.
+func (PingPayload) Validate() error { return nil }
+
+// PointsCommand is the data of an award_points or deduct_points command.
+// Enrolled students are identified by StudentID; guests by their seat number.
+type PointsCommand struct {
+	StudentID  *uint  `json:"studentId,omitempty"`
+	SeatNumber *int   `json:"seatNumber,omitempty"`
+	Points     int    `json:"points"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Validate implements ClientPayload.
+func (p PointsCommand) Validate() error {
+	if p.StudentID == nil && (p.SeatNumber == nil || *p.SeatNumber <= 0) {
+		return errors.New("studentId or seatNumber is required")
+	}
+	if p.Points <= 0 {
+		return errors.New("points must be a positive number")
+	}
+	return nil
+}
+
+// RemoveStudentCommand is the data of a remove_student command, and the request body for removing
+// someone from the current session. Enrolled students are identified by StudentID; guests by their seat number.
+type RemoveStudentCommand struct {
+	StudentID  *uint `json:"studentId,omitempty"`
+	SeatNumber *int  `json:"seatNumber,omitempty"`
+}
+
+// Validate implements ClientPayload.
+func (p RemoveStudentCommand) Validate() error {
+	if p.StudentID == nil && (p.SeatNumber == nil || *p.SeatNumber <= 0) {
+		return errors.New("studentId or seatNumber is required")
+	}
+	return nil
+}
+
+// MoveStudentCommand is the data of a move_student command.
+// Enrolled students are identified by StudentID; guests by the seat they are in.
+type MoveStudentCommand struct {
+	StudentID *uint `json:"studentId,omitempty"`
+	FromSeat  *int  `json:"fromSeat,omitempty"`
+	ToSeat    int   `json:"toSeat"`
+}
+
+// Validate implements ClientPayload.
+func (p MoveStudentCommand) Validate() error {
+	if p.StudentID == nil && (p.FromSeat == nil || *p.FromSeat <= 0) {
+		return errors.New("studentId or fromSeat is required")
+	}
+	if p.ToSeat <= 0 {
+		return errors.New("toSeat must be a positive seat number")
+	}
+	return nil
+}
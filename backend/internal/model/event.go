@@ -0,0 +1,258 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProtocolVersion is the version of the WebSocket message protocol.
+// It is sent on every message and changes whenever a message shape changes incompatibly.
+const ProtocolVersion = 1
+
+// Server-to-client WebSocket event types.
+const (
+	EventClassState     = "class_state"
+	EventResyncRequired = "resync_required"
+	EventStudentJoined  = "student_joined"
+	EventStudentLeft    = "student_left"
+	EventPointsChanged  = "points_changed"
+	EventGroupChanged   = "group_changed"
+	EventSessionStarted = "session_started"
+	EventSessionPaused  = "session_paused"
+	EventSessionResumed = "session_resumed"
+	EventSessionEnded   = "session_ended"
+	EventJoinLock       = "join_lock_changed"
+	EventStudentMoved   = "student_moved"
+	EventGuestPromoted  = "guest_promoted"
+	EventClassFull      = "class_full"
+	EventSeatAvailable  = "seat_available"
+	EventJoinRequested  = "join_requested"
+	EventJoinDecided    = "join_decided"
+	EventPong           = "pong"
+	EventAck            = "ack"
+	EventError          = "error"
+)
+
+// Client-to-server WebSocket message types.
+const (
+	ClientMessagePing          = "ping"
+	ClientMessageAwardPoints   = "award_points"
+	ClientMessageDeductPoints  = "deduct_points"
+	ClientMessageMoveStudent   = "move_student"
+	ClientMessageRemoveStudent = "remove_student"
+	ClientMessageRegroup       = "regroup"
+	ClientMessageApproveJoin   = "approve_join"
+	ClientMessageRejectJoin    = "reject_join"
+)
+
+// Error codes sent in ErrorEvent.
+const (
+	ErrorCodeInvalidMessage = "invalid_message"
+	ErrorCodeForbidden      = "forbidden"
+	ErrorCodeNotFound       = "not_found"
+	ErrorCodeConflict       = "conflict"
+	ErrorCodeInternal       = "internal"
+)
+
+// Event is the payload of a server-to-client WebSocket message.
+type Event interface {
+	// EventType returns the message type the payload is sent as.
+	EventType() string
+}
+
+// NewWebSocketMessage wraps an event for a class in a message of the current protocol version.
+func NewWebSocketMessage(classID string, event Event) WebSocketMessage {
+	return WebSocketMessage{
+		Version:   ProtocolVersion,
+		Type:      event.EventType(),
+		ClassID:   classID,
+		Data:      event,
+		Timestamp: time.Now(),
+	}
+}
+
+// EventType implements Event.
+func (ClassState) EventType() string { return EventClassState }
+
+// ResyncRequiredEvent tells a reconnecting client that the messages it missed are no longer buffered,
+// so it must reload the class state instead of relying on replay.
+type ResyncRequiredEvent struct {
+	LatestSeq uint64 `json:"latestSeq"`
+}
+
+// EventType implements Event.
+func (ResyncRequiredEvent) EventType() string { return EventResyncRequired }
+
+// StudentJoinedEvent reports a student or guest joining the class.
+// SessionID and IsLate are set when the join was recorded in the current session's attendance,
+// in which case SeatNumber is the seat assigned for the session.
+type StudentJoinedEvent struct {
+	StudentID  *uint  `json:"studentId,omitempty"`
+	Name       string `json:"name"`
+	SeatNumber int    `json:"seatNumber"`
+	IsGuest    bool   `json:"isGuest"`
+	SessionID  *uint  `json:"sessionId,omitempty"`
+	IsLate     bool   `json:"isLate"`
+}
+
+// EventType implements Event.
+func (StudentJoinedEvent) EventType() string { return EventStudentJoined }
+
+// ClassFullEvent reports a join refused, or wait-listed, because the current session is full.
+type ClassFullEvent struct {
+	SessionID      uint   `json:"sessionId"`
+	Name           string `json:"name"`
+	Capacity       int    `json:"capacity"`
+	Attending      int    `json:"attending"`
+	Waitlisted     bool   `json:"waitlisted"`
+	WaitlistLength int    `json:"waitlistLength"`
+}
+
+// EventType implements Event.
+func (ClassFullEvent) EventType() string { return EventClassFull }
+
+// SeatAvailableEvent reports a seat freed in a session with joiners on its waiting list.
+// Next is the joiner who is admitted when they join again.
+type SeatAvailableEvent struct {
+	SessionID      uint          `json:"sessionId"`
+	SeatNumber     int           `json:"seatNumber"`
+	Next           WaitlistEntry `json:"next"`
+	WaitlistLength int           `json:"waitlistLength"`
+}
+
+// EventType implements Event.
+func (SeatAvailableEvent) EventType() string { return EventSeatAvailable }
+
+// JoinRequestEvent reports a join waiting for the teacher's approval (join_requested),
+// or a decision on one (join_decided); its message type is set by Type.
+type JoinRequestEvent struct {
+	Type    string      `json:"-"`
+	Request JoinRequest `json:"request"`
+}
+
+// EventType implements Event.
+func (e JoinRequestEvent) EventType() string { return e.Type }
+
+// Reasons a student or guest leaves the current session, sent in StudentLeftEvent.
+const (
+	LeaveReasonLeft    = "left"
+	LeaveReasonRemoved = "removed"
+)
+
+// StudentLeftEvent reports a student or guest leaving the class, on their own or removed by the teacher.
+// SeatNumber is the seat they freed.
+type StudentLeftEvent struct {
+	StudentID  *uint  `json:"studentId,omitempty"`
+	Name       string `json:"name"`
+	SeatNumber int    `json:"seatNumber"`
+	Reason     string `json:"reason"`
+}
+
+// EventType implements Event.
+func (StudentLeftEvent) EventType() string { return EventStudentLeft }
+
+// PointsChangedEvent reports a point ledger entry and the resulting balance of a student or guest seat.
+type PointsChangedEvent struct {
+	SessionID  uint   `json:"sessionId"`
+	StudentID  *uint  `json:"studentId,omitempty"`
+	SeatNumber *int   `json:"seatNumber,omitempty"`
+	Delta      int    `json:"delta"`
+	Total      int    `json:"total"`
+	Reason     string `json:"reason"`
+}
+
+// EventType implements Event.
+func (PointsChangedEvent) EventType() string { return EventPointsChanged }
+
+// GroupChangedEvent reports the groups formed for a class session, replacing any it had before.
+type GroupChangedEvent struct {
+	SessionID uint           `json:"sessionId"`
+	Groups    []StudentGroup `json:"groups"`
+}
+
+// EventType implements Event.
+func (GroupChangedEvent) EventType() string { return EventGroupChanged }
+
+// SessionChangedEvent reports a session lifecycle change; its message type is the transition.
+type SessionChangedEvent struct {
+	Type    string       `json:"-"`
+	Session ClassSession `json:"session"`
+}
+
+// EventType implements Event.
+func (e SessionChangedEvent) EventType() string { return e.Type }
+
+// JoinLockEvent reports the teacher locking or unlocking joins to the current session, or joins
+// locking on their own at Session.JoinsLockAt, which is announced with session_started.
+type JoinLockEvent struct {
+	Session ClassSession `json:"session"`
+}
+
+// EventType implements Event.
+func (JoinLockEvent) EventType() string { return EventJoinLock }
+
+// StudentMovedEvent reports a student or guest changing seats during the current session.
+type StudentMovedEvent struct {
+	StudentID *uint  `json:"studentId,omitempty"`
+	Name      string `json:"name"`
+	FromSeat  int    `json:"fromSeat"`
+	ToSeat    int    `json:"toSeat"`
+}
+
+// EventType implements Event.
+func (StudentMovedEvent) EventType() string { return EventStudentMoved }
+
+// GuestPromotedEvent reports the guest in a seat of the current session becoming an enrolled student,
+// who keeps the seat, attendance and points of the session.
+type GuestPromotedEvent struct {
+	SessionID           uint   `json:"sessionId"`
+	StudentID           uint   `json:"studentId"`
+	Name                string `json:"name"`
+	SeatNumber          int    `json:"seatNumber"`
+	PreferredSeatNumber int    `json:"preferredSeatNumber"`
+}
+
+// EventType implements Event.
+func (GuestPromotedEvent) EventType() string { return EventGuestPromoted }
+
+// PongEvent answers a client ping.
+type PongEvent struct {
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// EventType implements Event.
+func (PongEvent) EventType() string { return EventPong }
+
+// AckEvent reports that a client command was applied. Result is the command's outcome, if it has one.
+type AckEvent struct {
+	RequestID string      `json:"requestId,omitempty"`
+	Result    interface{} `json:"result,omitempty"`
+}
+
+// EventType implements Event.
+func (AckEvent) EventType() string { return EventAck }
+
+// ErrorEvent reports a client message the server could not accept or apply.
+type ErrorEvent struct {
+	RequestID string `json:"requestId,omitempty"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// EventType implements Event.
+func (ErrorEvent) EventType() string { return EventError }
+
+// ClientMessage is a message sent by a dashboard over the WebSocket.
+// Data is decoded according to Type once the envelope has been validated.
+type ClientMessage struct {
+	Version   int             `json:"version"`
+	Type      string          `json:"type"`
+	RequestID string          `json:"requestId,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// ClientPayload is the data of a client-to-server message.
+type ClientPayload interface {
+	// Validate reports a field that is missing or out of range.
+	Validate() error
+}
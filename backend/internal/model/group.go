@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Grouping strategies.
+const (
+	// GroupingSequential fills groups in seat order, enrolled students first and guests after.
+	GroupingSequential = "sequential"
+	// GroupingRandom shuffles everyone before filling groups.
+	GroupingRandom = "random"
+	// GroupingBalanced spreads point balances evenly across groups.
+	GroupingBalanced = "balanced"
+	// GroupingFreshMix keeps apart students who have often been grouped together in past sessions.
+	GroupingFreshMix = "fresh_mix"
+)
+
+// DefaultGroupSize is the group size used when a grouping request sets neither a size nor a count.
+const DefaultGroupSize = 5
+
+// StudentGroup is a named group of students formed for a class session.
+type StudentGroup struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	SessionID uint          `json:"sessionId" gorm:"not null;index"`
+	ClassID   string        `json:"classId" gorm:"not null;index"`
+	Name      string        `json:"name" gorm:"not null"`
+	Position  int           `json:"position"`
+	Strategy  string        `json:"strategy"`
+	Members   []GroupMember `json:"members" gorm:"foreignKey:GroupID"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// TableName sets the table name for the StudentGroup model
+func (StudentGroup) TableName() string {
+	return "student_groups"
+}
+
+// GroupMember is a student or guest placed in a group.
+type GroupMember struct {
+	ID         uint   `json:"-" gorm:"primaryKey"`
+	GroupID    uint   `json:"-" gorm:"not null;index"`
+	StudentID  *uint  `json:"studentId,omitempty"`
+	Name       string `json:"name"`
+	SeatNumber int    `json:"seatNumber"`
+}
+
+// TableName sets the table name for the GroupMember model
+func (GroupMember) TableName() string {
+	return "student_group_members"
+}
+
+// GroupingRequest is the request body for forming groups, and the data of a regroup command.
+// At most one of GroupSize and GroupCount may be set; with neither, groups of DefaultGroupSize are formed.
+// PresentOnly leaves out enrolled students who have not joined the session.
+type GroupingRequest struct {
+	Strategy    string `json:"strategy,omitempty"`
+	GroupSize   int    `json:"groupSize,omitempty"`
+	GroupCount  int    `json:"groupCount,omitempty"`
+	PresentOnly bool   `json:"presentOnly,omitempty"`
+}
+
+// Validate implements ClientPayload.
+func (r GroupingRequest) Validate() error {
+	if r.GroupSize < 0 || r.GroupCount < 0 {
+		return errors.New("groupSize and groupCount must be positive numbers")
+	}
+	if r.GroupSize > 0 && r.GroupCount > 0 {
+		return errors.New("set either groupSize or groupCount, not both")
+	}
+	return nil
+}
+
+// PairingMatrix counts how many sessions each pair of a class's enrolled students shared a group in.
+// Counts[i][j] is the count for Students[i] and Students[j]; the diagonal counts the sessions each student was grouped in.
+type PairingMatrix struct {
+	ClassID  string          `json:"classId"`
+	Sessions int             `json:"sessions"`
+	Students []MatrixStudent `json:"students"`
+	Counts   [][]int         `json:"counts"`
+}
+
+// MatrixStudent identifies a row and column of a PairingMatrix.
+type MatrixStudent struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
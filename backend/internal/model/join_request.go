@@ -0,0 +1,60 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Join request statuses.
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// JoinCodeApprovalPending is returned in APIResponse.Code when a join waits for the teacher's approval.
+const JoinCodeApprovalPending = "approval_pending"
+
+// JoinRequest is a join to a class that requires approval, waiting for or decided by the teacher.
+// The joining device follows the request by its PollToken, which is only returned to that device,
+// and receives the SessionToken issued when the request is approved. NameKey, the normalized name,
+// keeps a name from waiting twice.
+type JoinRequest struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	ClassID      string     `json:"classId" gorm:"not null;index"`
+	StudentID    *uint      `json:"studentId,omitempty"`
+	Name         string     `json:"name" gorm:"not null"`
+	NameKey      string     `json:"-" gorm:"not null"`
+	Status       string     `json:"status" gorm:"not null;default:pending"`
+	PollToken    string     `json:"-" gorm:"uniqueIndex;not null"`
+	SessionToken string     `json:"-"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	DecidedAt    *time.Time `json:"decidedAt,omitempty"`
+}
+
+// TableName sets the table name for the JoinRequest model
+func (JoinRequest) TableName() string {
+	return "join_requests"
+}
+
+// JoinRequestStatus is the response data for a joining device following its join request.
+// RedirectURL, carrying the student's session token, is set once the request is approved.
+type JoinRequestStatus struct {
+	RequestID   uint   `json:"requestId"`
+	Status      string `json:"status"`
+	PollURL     string `json:"pollUrl"`
+	RedirectURL string `json:"redirectUrl,omitempty"`
+}
+
+// JoinDecisionCommand is the data of an approve_join or reject_join command.
+type JoinDecisionCommand struct {
+	RequestID uint `json:"requestId"`
+}
+
+// Validate implements ClientPayload.
+func (p JoinDecisionCommand) Validate() error {
+	if p.RequestID == 0 {
+		return errors.New("requestId is required")
+	}
+	return nil
+}
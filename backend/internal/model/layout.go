@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// DefaultLayoutColumns is the width of the seating grid of a class without a saved layout,
+// matching the dashboard's seat grid.
+const DefaultLayoutColumns = 5
+
+// Seat policies choose the seat given to a guest, or to a student without a free preferred seat, on joining a session.
+const (
+	// SeatPolicyNextFree gives the lowest-numbered free seat.
+	SeatPolicyNextFree = "next_free"
+	// SeatPolicyFrontFirst fills the front row first, each row from its middle outwards.
+	SeatPolicyFrontFirst = "front_first"
+	// SeatPolicyFillByZone fills the zones in the order they are listed, then the seats outside any zone.
+	SeatPolicyFillByZone = "fill_by_zone"
+)
+
+// SeatingLayout is the seating chart of a class: a grid of Rows by Columns cells, numbered row by row
+// from 1, in which disabled cells hold no seat. Zones name groups of seats, such as a lab bench.
+// Row 1 is the front of the room. SeatPolicy is how seats are assigned to those joining without one.
+type SeatingLayout struct {
+	ClassID       string     `json:"classId" gorm:"primaryKey"`
+	Rows          int        `json:"rows" gorm:"not null"`
+	Columns       int        `json:"columns" gorm:"not null"`
+	DisabledSeats []int      `json:"disabledSeats" gorm:"type:jsonb;serializer:json"`
+	Zones         []SeatZone `json:"zones" gorm:"type:jsonb;serializer:json"`
+	SeatPolicy    string     `json:"seatPolicy" gorm:"not null;default:next_free"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// TableName sets the table name for the SeatingLayout model
+func (SeatingLayout) TableName() string {
+	return "seating_layouts"
+}
+
+// SeatZone is a named set of seats in a seating layout.
+type SeatZone struct {
+	Name  string `json:"name"`
+	Seats []int  `json:"seats"`
+}
+
+// SeatingLayoutRequest is the request body for replacing a class's seating layout.
+// SeatPolicy defaults to SeatPolicyNextFree.
+type SeatingLayoutRequest struct {
+	Rows          int        `json:"rows"`
+	Columns       int        `json:"columns"`
+	DisabledSeats []int      `json:"disabledSeats"`
+	Zones         []SeatZone `json:"zones"`
+	SeatPolicy    string     `json:"seatPolicy,omitempty"`
+}
+
+// HasSeat reports whether a seat number is an enabled cell of the layout.
+func (l *SeatingLayout) HasSeat(seat int) bool {
+	if seat <= 0 || seat > l.Rows*l.Columns {
+		return false
+	}
+	for _, disabled := range l.DisabledSeats {
+		if disabled == seat {
+			return false
+		}
+	}
+	return true
+}
+
+// Seats lists the layout's seat numbers in order.
+func (l *SeatingLayout) Seats() []int {
+	var seats []int
+	for seat := 1; seat <= l.Rows*l.Columns; seat++ {
+		if l.HasSeat(seat) {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Pairing constraint kinds.
+const (
+	// PairingKeepApart puts two students in different groups.
+	PairingKeepApart = "apart"
+	// PairingKeepTogether puts two students in the same group.
+	PairingKeepTogether = "together"
+)
+
+// PairingConstraint is a rule between two students of a class that group formation must honor.
+// StudentAID is always the lower of the two student IDs, so a pair has at most one rule.
+type PairingConstraint struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ClassID    string    `json:"classId" gorm:"not null;index"`
+	Kind       string    `json:"kind" gorm:"not null"`
+	StudentAID uint      `json:"studentAId" gorm:"not null"`
+	StudentBID uint      `json:"studentBId" gorm:"not null"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName sets the table name for the PairingConstraint model
+func (PairingConstraint) TableName() string {
+	return "pairing_constraints"
+}
+
+// PairingConstraintRequest is the request body for adding a pairing constraint.
+type PairingConstraintRequest struct {
+	Kind       string `json:"kind"`
+	StudentIDs []uint `json:"studentIds"`
+}
+
+// Validate reports a request that does not name a known kind and two different students.
+func (r PairingConstraintRequest) Validate() error {
+	if r.Kind != PairingKeepApart && r.Kind != PairingKeepTogether {
+		return errors.New("kind must be 'apart' or 'together'")
+	}
+	if len(r.StudentIDs) != 2 || r.StudentIDs[0] == r.StudentIDs[1] {
+		return errors.New("studentIds must name two different students")
+	}
+	return nil
+}
+
+// UnsatisfiedConstraint is a pairing constraint that groups of the requested size could not honor, and why.
+type UnsatisfiedConstraint struct {
+	Constraint PairingConstraint `json:"constraint"`
+	Students   []string          `json:"students"`
+	Reason     string            `json:"reason"`
+}
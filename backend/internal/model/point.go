@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// PointEvent is a single entry in a class session's point ledger.
+// Enrolled students are identified by StudentID; guests are identified by their seat number,
+// and once a guest leaves, by AttendanceID, the attendance record of their stay in the seat.
+type PointEvent struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ClassID      string    `json:"classId" gorm:"not null;index"`
+	SessionID    uint      `json:"sessionId" gorm:"index"`
+	StudentID    *uint     `json:"studentId,omitempty" gorm:"index"`
+	SeatNumber   *int      `json:"seatNumber,omitempty"`
+	AttendanceID *uint     `json:"attendanceId,omitempty"`
+	Delta        int       `json:"delta" gorm:"not null"`
+	Reason       string    `json:"reason"`
+	Teacher      string    `json:"teacher"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// TableName sets the table name for the PointEvent model
+func (PointEvent) TableName() string {
+	return "point_events"
+}
+
+// PointTotal is the computed point balance of a student or guest seat.
+type PointTotal struct {
+	StudentID  *uint `json:"studentId,omitempty"`
+	SeatNumber *int  `json:"seatNumber,omitempty"`
+	Total      int   `json:"total"`
+}
+
+// PointRequest is the request body for awarding or deducting points.
+// The teacher recorded on the event is the authenticated one, never taken from the body.
+type PointRequest struct {
+	StudentID  *uint  `json:"studentId"`
+	SeatNumber *int   `json:"seatNumber"`
+	Points     int    `json:"points"`
+	Reason     string `json:"reason"`
+}
+
+// PointUpdateResponse is the response for a recorded point event with the resulting balance.
+type PointUpdateResponse struct {
+	Event PointEvent `json:"event"`
+	Total int        `json:"total"`
+}
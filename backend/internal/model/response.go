@@ -3,17 +3,23 @@ package model
 import "time"
 
 // APIResponse is the standard API response model.
+// Code is a machine-readable outcome, set where clients must tell apart responses sharing a status.
 type APIResponse struct {
 	Success bool        `json:"success"`
+	Code    string      `json:"code,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Message string      `json:"message,omitempty"`
 	Errors  []string    `json:"errors,omitempty"`
 }
 
 // WebSocketMessage represents a message sent over WebSocket.
+// Data holds the Event named by Type. Seq increases by one for each message published to a class,
+// so clients can detect and replay gaps.
 type WebSocketMessage struct {
+	Version   int         `json:"version"`
 	Type      string      `json:"type"`
 	ClassID   string      `json:"classId"`
+	Seq       uint64      `json:"seq,omitempty"`
 	Data      interface{} `json:"data"`
 	Timestamp time.Time   `json:"timestamp"`
 }
@@ -30,4 +36,3 @@ type QRCodeResponse struct {
 	Data    QRCodeData `json:"data"`
 	Message string     `json:"message"`
 }
-
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Class session statuses.
+const (
+	SessionStatusActive = "active"
+	SessionStatusPaused = "paused"
+	SessionStatusEnded  = "ended"
+)
+
+// ClassSession represents a single lesson of a class, from start to end.
+// A class has at most one session that has not ended (its current session).
+// Joining is refused once the teacher locks the session or its JoinsLockAt time passes.
+type ClassSession struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	ClassID     string     `json:"classId" gorm:"not null;index"`
+	Status      string     `json:"status" gorm:"not null;default:active"`
+	StartedAt   time.Time  `json:"startedAt"`
+	PausedAt    *time.Time `json:"pausedAt,omitempty"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"`
+	JoinsLocked bool       `json:"joinsLocked"`
+	JoinsLockAt *time.Time `json:"joinsLockAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName sets the table name for the ClassSession model
+func (ClassSession) TableName() string {
+	return "class_sessions"
+}
+
+// JoinsLockedAt reports whether joining the session is refused at the given time.
+func (s *ClassSession) JoinsLockedAt(now time.Time) bool {
+	return s.JoinsLocked || s.JoinsLockAt != nil && !now.Before(*s.JoinsLockAt)
+}
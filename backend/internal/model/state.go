@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// ClassState is a snapshot of who is in the room for a class's current session, and how they are grouped.
+// Seq is the class's last WebSocket sequence number when the snapshot was taken;
+// a client can apply later messages on top of it by connecting with ?since=<seq>.
+type ClassState struct {
+	ClassID  string         `json:"classId"`
+	Seq      uint64         `json:"seq"`
+	Session  *ClassSession  `json:"session"`
+	Students []LiveStudent  `json:"students"`
+	Groups   []StudentGroup `json:"groups"`
+}
+
+// LiveStudent is a student or guest who has joined the current session, with their seat and point balance.
+type LiveStudent struct {
+	StudentID  *uint     `json:"studentId,omitempty"`
+	Name       string    `json:"name"`
+	SeatNumber int       `json:"seatNumber"`
+	IsGuest    bool      `json:"isGuest"`
+	IsLate     bool      `json:"isLate"`
+	JoinedAt   time.Time `json:"joinedAt"`
+	Points     int       `json:"points"`
+}
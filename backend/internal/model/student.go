@@ -3,9 +3,13 @@ package model
 import "time"
 
 // Student represents a student (independent of classes).
+// NameKey is the normalized name joins are matched on; the service sets it whenever the name changes.
+// OwnerID is the teacher who created the student; only they may rename, delete or enroll them.
 type Student struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	Name      string    `json:"name" gorm:"not null"`
+	NameKey   string    `json:"-" gorm:"not null;index"`
+	OwnerID   *uint     `json:"ownerId,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -18,7 +22,7 @@ type StudentPreferredSeat struct {
 	PreferredSeatNumber int       `json:"preferredSeatNumber" gorm:"not null"`
 	CreatedAt           time.Time `json:"createdAt" gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt           time.Time `json:"updatedAt"`
-	
+
 	// Foreign key relationships
 	Student Student `json:"student" gorm:"foreignKey:StudentID"`
 	Class   Class   `json:"class" gorm:"foreignKey:ClassID"`
@@ -39,3 +43,49 @@ type StudentWithClassPreferredSeat struct {
 	UpdatedAt           time.Time `json:"updatedAt"`
 }
 
+// StudentRequest is the request body for adding or renaming a student.
+type StudentRequest struct {
+	Name string `json:"name"`
+}
+
+// EnrollmentRequest is the request body for enrolling a student in a class with a preferred seat.
+type EnrollmentRequest struct {
+	StudentID  uint `json:"studentId"`
+	SeatNumber int  `json:"seatNumber"`
+}
+
+// GuestPromotionRequest is the request body for enrolling the guest in a seat of the current session
+// as a new student. Name defaults to the name the guest joined with, and PreferredSeatNumber to their seat.
+type GuestPromotionRequest struct {
+	SeatNumber          int    `json:"seatNumber"`
+	Name                string `json:"name"`
+	PreferredSeatNumber int    `json:"preferredSeatNumber"`
+}
+
+// NameChoice is one of the students a joiner can pick when the name they typed matches several.
+// Label identifies the student without their full name, by last initial and seat.
+type NameChoice struct {
+	StudentID  uint   `json:"studentId"`
+	Label      string `json:"label"`
+	SeatNumber int    `json:"seatNumber,omitempty"`
+}
+
+// RosterImportRow is a single parsed row of a roster CSV upload.
+type RosterImportRow struct {
+	Row        int    `json:"row"`
+	Name       string `json:"name"`
+	SeatNumber int    `json:"seatNumber"`
+}
+
+// RosterImportError describes why a roster CSV row was not imported.
+type RosterImportError struct {
+	Row   int    `json:"row"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// RosterImportResult reports the outcome of a roster CSV upload.
+type RosterImportResult struct {
+	Imported []StudentWithClassPreferredSeat `json:"imported"`
+	Errors   []RosterImportError             `json:"errors"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Teacher is a dashboard user who owns classes.
+type Teacher struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Email        string    `json:"email" gorm:"uniqueIndex;not null"`
+	Name         string    `json:"name" gorm:"not null"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName sets the table name for the Teacher model
+func (Teacher) TableName() string {
+	return "teachers"
+}
+
+// RegisterTeacherRequest is the request body for creating a teacher account.
+type RegisterTeacherRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginRequest is the request body for teacher login.
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// AuthResponse is returned after a teacher registers or logs in.
+type AuthResponse struct {
+	Token     string   `json:"token"`
+	ExpiresAt int64    `json:"expiresAt"`
+	Teacher   *Teacher `json:"teacher"`
+}
+
+// TeacherClaims identifies the teacher an access token was issued to.
+type TeacherClaims struct {
+	Type      string `json:"typ"`
+	TeacherID uint   `json:"teacherId"`
+	Email     string `json:"email"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TeacherTokenType marks teacher access tokens so student session tokens cannot be used in their place.
+const TeacherTokenType = "teacher"
+
+// Expiry returns when the claims stop being valid.
+func (c *TeacherClaims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
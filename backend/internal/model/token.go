@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// StudentSessionClaims identifies the student a device joined a class as.
+// They are signed into the token appended to the join redirect.
+// AttendanceID is the attendance record of the join, which the token is only valid for while it stands.
+type StudentSessionClaims struct {
+	Type         string `json:"typ"`
+	TokenID      string `json:"jti"`
+	StudentID    *uint  `json:"studentId,omitempty"`
+	Name         string `json:"name"`
+	ClassID      string `json:"classId"`
+	SessionID    *uint  `json:"sessionId,omitempty"`
+	AttendanceID *uint  `json:"attendanceId,omitempty"`
+	SeatNumber   int    `json:"seatNumber"`
+	IssuedAt     int64  `json:"iat"`
+	ExpiresAt    int64  `json:"exp"`
+}
+
+// StudentTokenType marks student session tokens so teacher access tokens cannot be used in their place.
+const StudentTokenType = "student"
+
+// Expiry returns when the claims stop being valid.
+func (c *StudentSessionClaims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// TokenVerifyRequest is the payload for verifying a student session token.
+type TokenVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Join outcome codes returned in APIResponse.Code when a join is refused or deferred.
+const (
+	JoinCodeClassInactive   = "class_inactive"
+	JoinCodeClassNotStarted = "class_not_started"
+	JoinCodeClassLocked     = "class_locked"
+	JoinCodeClassFull       = "class_full"
+	JoinCodeWaitlisted      = "waitlisted"
+	JoinCodeNameAmbiguous   = "name_ambiguous"
+)
+
+// WaitlistEntry is a student or guest waiting for a seat in a full class session.
+// Entries are served in the order they were created. Guests are told apart by NameKey, their normalized name.
+type WaitlistEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	SessionID uint      `json:"sessionId" gorm:"not null;index"`
+	ClassID   string    `json:"classId" gorm:"not null;index"`
+	StudentID *uint     `json:"studentId,omitempty"`
+	Name      string    `json:"name" gorm:"not null"`
+	NameKey   string    `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName sets the table name for the WaitlistEntry model
+func (WaitlistEntry) TableName() string {
+	return "join_waitlist"
+}
+
+// WaitlistPosition is the response data for a join placed on the waiting list.
+type WaitlistPosition struct {
+	SessionID uint `json:"sessionId"`
+	Position  int  `json:"position"`
+}
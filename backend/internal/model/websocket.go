@@ -1,19 +1,41 @@
 package model
 
 import (
-	"github.com/gorilla/websocket"
+	"time"
 )
 
+// Conn is the part of a WebSocket connection the hub writes to; *websocket.Conn satisfies it
+type Conn interface {
+	WriteMessage(messageType int, data []byte) error
+	SetWriteDeadline(t time.Time) error
+	Close() error
+}
+
 // Client represents a WebSocket client connection
 type Client struct {
-	Conn    *websocket.Conn
+	Conn    Conn
 	ClassID string
+
+	// TeacherID is the authenticated teacher on the connection, used to authorize their commands;
+	// TeacherEmail is recorded as the teacher behind the point events they send
+	TeacherID    uint
+	TeacherEmail string
+
+	// Send queues encoded messages for the client's writer goroutine; the hub creates and closes it
+	Send chan []byte
+
+	// Resume requests replay of the class messages published after sequence number Since
+	Resume bool
+	Since  uint64
+
+	// Initial is queued ahead of any replayed or live message, e.g. a class state snapshot
+	Initial *WebSocketMessage
 }
 
 // WebSocketHub manages WebSocket connections for a class
 type WebSocketHub struct {
 	// Registered clients for each class
-	Clients map[string]map[*websocket.Conn]bool
+	Clients map[string]map[*Client]bool
 
 	// Channel for broadcasting messages to clients
 	Broadcast chan WebSocketMessage
@@ -23,4 +45,11 @@ type WebSocketHub struct {
 
 	// Unregister requests from clients
 	Unregister chan *Client
-}
\ No newline at end of file
+}
+
+// WebSocketStats reports hub connection and slow-consumer counters
+type WebSocketStats struct {
+	Connections     int    `json:"connections"`
+	DroppedMessages uint64 `json:"droppedMessages"`
+	EvictedClients  uint64 `json:"evictedClients"`
+}
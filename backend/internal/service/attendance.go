@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+// IsLateJoin reports whether a join at joinedAt is late for the session.
+func IsLateJoin(session *model.ClassSession, joinedAt time.Time, lateAfter time.Duration) bool {
+	return joinedAt.After(session.StartedAt.Add(lateAfter))
+}
+
+// RecordAttendance stores a join in its session's attendance.
+// A student (or guest name) already recorded in the session keeps the original record,
+// which is loaded into record.
+func RecordAttendance(db *gorm.DB, record *model.AttendanceRecord) error {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	existing, err := findAttendance(db, record.SessionID, record.StudentID, record.Name)
+	if err != nil {
+		return err
+	}
+	*record = *existing
+	return nil
+}
+
+// findAttendance fetches the record of a student, or of a guest name by its key, still in a session.
+func findAttendance(db *gorm.DB, sessionID uint, studentID *uint, name string) (*model.AttendanceRecord, error) {
+	var record model.AttendanceRecord
+	query := db.Where("session_id = ? AND left_at IS NULL", sessionID)
+	if studentID != nil {
+		query = query.Where("student_id = ?", *studentID)
+	} else {
+		query = query.Where("student_id IS NULL AND name_key = ?", studentNameKey(name))
+	}
+	if err := query.First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// CountAttending counts those still in a session, each of whom takes up one of the class's seats.
+func CountAttending(db *gorm.DB, sessionID uint) (int, error) {
+	var attending int64
+	if err := db.Model(&model.AttendanceRecord{}).Where("session_id = ? AND left_at IS NULL", sessionID).Count(&attending).Error; err != nil {
+		return 0, err
+	}
+	return int(attending), nil
+}
+
+// SeatsTaken counts the seats taken in the class's open session, which is 0 when no session is open.
+func SeatsTaken(db *gorm.DB, classID string) (int, error) {
+	session, err := GetCurrentSession(db, classID)
+	if errors.Is(err, ErrNoActiveSession) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return CountAttending(db, session.ID)
+}
+
+// ListAttendance fetches the attendance records of a session in join order, including those who have left.
+func ListAttendance(db *gorm.DB, sessionID uint) ([]model.AttendanceRecord, error) {
+	var records []model.AttendanceRecord
+	result := db.Where("session_id = ?", sessionID).Order("joined_at, id").Find(&records)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return records, nil
+}
+
+// ListAttending fetches the attendance records of those still in a session in join order.
+func ListAttending(db *gorm.DB, sessionID uint) ([]model.AttendanceRecord, error) {
+	var records []model.AttendanceRecord
+	result := db.Where("session_id = ? AND left_at IS NULL", sessionID).Order("joined_at, id").Find(&records)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return records, nil
+}
+
+// ListAbsentStudents fetches the students enrolled in a class who never joined the session.
+func ListAbsentStudents(db *gorm.DB, classID string, sessionID uint) ([]model.StudentWithClassPreferredSeat, error) {
+	var students []model.StudentWithClassPreferredSeat
+	result := db.Table("student_preferred_seats AS sps").
+		Select("s.id, s.name, sps.class_id, sps.preferred_seat_number, sps.created_at, sps.updated_at").
+		Joins("JOIN students s ON s.id = sps.student_id").
+		Where("sps.class_id = ?", classID).
+		Where("NOT EXISTS (SELECT 1 FROM attendance_records ar WHERE ar.session_id = ? AND ar.student_id = sps.student_id)", sessionID).
+		Order("sps.preferred_seat_number").
+		Scan(&students)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return students, nil
+}
+
+// GetAttendanceReport builds the present, late and absent lists for a class session.
+func GetAttendanceReport(db *gorm.DB, classID string, sessionID uint) (*model.AttendanceReport, error) {
+	present, err := ListAttendance(db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	absent, err := ListAbsentStudents(db, classID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.AttendanceReport{
+		SessionID: sessionID,
+		Present:   present,
+		Late:      []model.AttendanceRecord{},
+		Absent:    absent,
+	}
+	for _, record := range present {
+		if record.IsLate {
+			report.Late = append(report.Late, record)
+		}
+	}
+	return report, nil
+}
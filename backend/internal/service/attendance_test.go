@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestIsLateJoin(t *testing.T) {
+	start := time.Date(2025, 6, 24, 9, 0, 0, 0, time.UTC)
+	session := &model.ClassSession{StartedAt: start}
+
+	if service.IsLateJoin(session, start.Add(5*time.Minute), 10*time.Minute) {
+		t.Error("expected join within threshold to be on time")
+	}
+	if !service.IsLateJoin(session, start.Add(11*time.Minute), 10*time.Minute) {
+		t.Error("expected join after threshold to be late")
+	}
+}
+
+func TestRecordAttendance_AlreadyRecorded(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(5)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "attendance_records" .* ON CONFLICT DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND student_id = \$2`).
+		WithArgs(uint(3), studentID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number", "is_late"}).
+			AddRow(9, 3, studentID, "Philip", 4, false))
+
+	record := &model.AttendanceRecord{SessionID: 3, ClassID: "class-1", StudentID: &studentID, Name: "Philip", IsLate: true}
+	if err := service.RecordAttendance(db, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.ID != 9 || record.IsLate || record.SeatNumber != 4 {
+		t.Errorf("expected original record to be kept, got %+v", record)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSeatsTaken(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	taken, err := service.SeatsTaken(db, "class-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if taken != 12 {
+		t.Errorf("expected 12 seats taken, got %d", taken)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSeatsTaken_NoSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	taken, err := service.SeatsTaken(db, "class-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if taken != 0 {
+		t.Errorf("expected no seats taken without a session, got %d", taken)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetAttendanceReport(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE session_id = \$1 ORDER BY joined_at, id`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number", "is_late"}).
+			AddRow(1, 3, 5, "Philip", 4, false).
+			AddRow(2, 3, nil, "Guest", 0, true))
+	mock.ExpectQuery(`SELECT s\.id, s\.name, sps\.class_id, sps\.preferred_seat_number, sps\.created_at, sps\.updated_at FROM student_preferred_seats AS sps JOIN students s ON s\.id = sps\.student_id WHERE sps\.class_id = \$1 AND \(NOT EXISTS`).
+		WithArgs("class-1", uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "class_id", "preferred_seat_number"}).
+			AddRow(6, "Darrell", "class-1", 7))
+
+	report, err := service.GetAttendanceReport(db, "class-1", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Present) != 2 {
+		t.Errorf("expected 2 present, got %d", len(report.Present))
+	}
+	if len(report.Late) != 1 || report.Late[0].Name != "Guest" {
+		t.Errorf("expected guest to be late, got %+v", report.Late)
+	}
+	if len(report.Absent) != 1 || report.Absent[0].Name != "Darrell" {
+		t.Errorf("expected Darrell to be absent, got %+v", report.Absent)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
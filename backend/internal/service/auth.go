@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+	"classswift-backend/pkg/token"
+)
+
+// minPasswordLength is the shortest password accepted for a teacher account.
+const minPasswordLength = 8
+
+var (
+	// ErrInvalidEmail is returned when a teacher email address cannot be parsed.
+	ErrInvalidEmail = errors.New("email address is invalid")
+	// ErrInvalidTeacherName is returned when a teacher name is empty.
+	ErrInvalidTeacherName = errors.New("teacher name must not be empty")
+	// ErrWeakPassword is returned when a password is shorter than minPasswordLength.
+	ErrWeakPassword = errors.New("password must be at least 8 characters")
+	// ErrEmailTaken is returned when a teacher account already uses the email address.
+	ErrEmailTaken = errors.New("email address is already registered")
+	// ErrInvalidCredentials is returned when a login email or password does not match.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+)
+
+// normalizeEmail lower-cases and trims an email address so lookups are case-insensitive.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// RegisterTeacher creates a teacher account with a bcrypt-hashed password.
+func RegisterTeacher(db *gorm.DB, req model.RegisterTeacherRequest) (*model.Teacher, error) {
+	teacher := &model.Teacher{
+		Email: normalizeEmail(req.Email),
+		Name:  strings.TrimSpace(req.Name),
+	}
+	if _, err := mail.ParseAddress(teacher.Email); err != nil {
+		return nil, ErrInvalidEmail
+	}
+	if teacher.Name == "" {
+		return nil, ErrInvalidTeacherName
+	}
+	if len(req.Password) < minPasswordLength {
+		return nil, ErrWeakPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	teacher.PasswordHash = string(hash)
+
+	if err := db.Create(teacher).Error; err != nil {
+		switch constraintViolation(err) {
+		case "unique_teacher_email":
+			return nil, ErrEmailTaken
+		case "chk_teacher_name_not_empty":
+			return nil, ErrInvalidTeacherName
+		}
+		return nil, err
+	}
+	return teacher, nil
+}
+
+// AuthenticateTeacher checks a teacher's email and password.
+func AuthenticateTeacher(db *gorm.DB, email string, password string) (*model.Teacher, error) {
+	var teacher model.Teacher
+	if err := db.Where("email = ?", normalizeEmail(email)).First(&teacher).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(teacher.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return &teacher, nil
+}
+
+// IssueTeacherToken signs an access token for a teacher using the configured secret and TTL.
+func IssueTeacherToken(teacher *model.Teacher) (string, *model.TeacherClaims, error) {
+	now := time.Now()
+	claims := &model.TeacherClaims{
+		Type:      model.TeacherTokenType,
+		TeacherID: teacher.ID,
+		Email:     teacher.Email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(config.TeacherTokenTTL()).Unix(),
+	}
+	signed, err := token.Sign(config.SessionTokenSecret(), claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// VerifyTeacherToken checks a teacher access token and returns its claims.
+// Student session tokens are rejected even though they share the signing secret.
+func VerifyTeacherToken(signed string) (*model.TeacherClaims, error) {
+	var claims model.TeacherClaims
+	if err := token.Verify(config.SessionTokenSecret(), signed, &claims, time.Now()); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Type != model.TeacherTokenType || claims.TeacherID == 0 {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// IsClassOwner reports whether the teacher owns the class.
+func IsClassOwner(class *model.Class, teacherID uint) bool {
+	return class.OwnerID != nil && *class.OwnerID == teacherID
+}
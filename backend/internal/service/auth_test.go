@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"golang.org/x/crypto/bcrypt"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestRegisterTeacher(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "teachers"`).
+		WithArgs("ada@example.com", "Ada", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	teacher, err := service.RegisterTeacher(db, model.RegisterTeacherRequest{Email: " Ada@Example.com ", Name: "Ada", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if teacher.ID != 1 || teacher.Email != "ada@example.com" {
+		t.Errorf("unexpected teacher: %+v", teacher)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(teacher.PasswordHash), []byte("correct-horse")) != nil {
+		t.Error("expected password to be stored as a bcrypt hash")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRegisterTeacher_Validation(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	cases := []struct {
+		req  model.RegisterTeacherRequest
+		want error
+	}{
+		{model.RegisterTeacherRequest{Email: "not-an-email", Name: "Ada", Password: "correct-horse"}, service.ErrInvalidEmail},
+		{model.RegisterTeacherRequest{Email: "ada@example.com", Name: "  ", Password: "correct-horse"}, service.ErrInvalidTeacherName},
+		{model.RegisterTeacherRequest{Email: "ada@example.com", Name: "Ada", Password: "short"}, service.ErrWeakPassword},
+	}
+	for _, tc := range cases {
+		if _, err := service.RegisterTeacher(db, tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("expected %v, got %v", tc.want, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAuthenticateTeacher(t *testing.T) {
+	db, mock := setupMockDB(t)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct-horse"), bcrypt.MinCost)
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(`SELECT \* FROM "teachers" WHERE email = \$1`).
+			WithArgs("ada@example.com", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "password_hash"}).AddRow(1, "ada@example.com", "Ada", string(hash)))
+	}
+
+	teacher, err := service.AuthenticateTeacher(db, "ADA@example.com", "correct-horse")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if teacher.ID != 1 {
+		t.Errorf("expected teacher 1, got %+v", teacher)
+	}
+	if _, err := service.AuthenticateTeacher(db, "ada@example.com", "wrong-password"); !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestVerifyTeacherToken_RejectsStudentToken(t *testing.T) {
+	config.Init()
+
+	signed, _, err := service.IssueTeacherToken(&model.Teacher{ID: 5, Email: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := service.VerifyTeacherToken(signed)
+	if err != nil || claims.TeacherID != 5 {
+		t.Fatalf("expected teacher 5, got %+v (%v)", claims, err)
+	}
+
+	studentToken, _, _ := service.IssueStudentToken(&model.JoinResult{Class: &model.Class{PublicID: "PUB1"}}, "Guest")
+	if _, err := service.VerifyTeacherToken(studentToken); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for a student token, got %v", err)
+	}
+}
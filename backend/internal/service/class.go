@@ -2,9 +2,15 @@ package service
 
 import (
 	"classswift-backend/internal/model"
+	"crypto/rand"
 	"errors"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GetClassByPublicID fetches a class by its public ID.
@@ -17,10 +23,10 @@ func GetClassByPublicID(db *gorm.DB, publicID string) (*model.Class, error) {
 	return &class, nil
 }
 
-// GetClasses fetches all classes from the class table.
-func GetClasses(db *gorm.DB) ([]model.Class, error) {
+// GetClasses fetches all classes owned by a teacher that have not been archived.
+func GetClasses(db *gorm.DB, ownerID uint) ([]model.Class, error) {
 	var classes []model.Class
-	result := db.Find(&classes)
+	result := db.Where("owner_id = ? AND archived_at IS NULL", ownerID).Find(&classes)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -29,17 +35,29 @@ func GetClasses(db *gorm.DB) ([]model.Class, error) {
 
 // CreateStudent adds a new student (independent of classes).
 func CreateStudent(db *gorm.DB, student *model.Student) error {
-	return db.Create(student).Error
+	student.Name = CleanStudentName(student.Name)
+	if err := validateStudentName(student.Name); err != nil {
+		return err
+	}
+	student.NameKey = studentNameKey(student.Name)
+	return translateEnrollmentError(db.Create(student).Error)
 }
 
-// GetStudentByName fetches a student by name only.
+// GetStudentByName fetches a student by name only, ignoring case, spacing and Unicode form.
+// Returns ErrAmbiguousName if several students have the name.
 func GetStudentByName(db *gorm.DB, name string) (*model.Student, error) {
-	var student model.Student
-	result := db.Where("name = ?", name).First(&student)
+	var students []model.Student
+	result := db.Where("name_key = ?", studentNameKey(name)).Order("id").Limit(2).Find(&students)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	return &student, nil
+	switch len(students) {
+	case 0:
+		return nil, gorm.ErrRecordNotFound
+	case 1:
+		return &students[0], nil
+	}
+	return nil, ErrAmbiguousName
 }
 
 // GetPreferredSeatByStudentAndClass fetches a preferred seat by student ID and class ID.
@@ -52,41 +70,263 @@ func GetPreferredSeatByStudentAndClass(db *gorm.DB, studentID uint, classID stri
 	return &preferredSeat, nil
 }
 
-// FindStudentPreferredSeat looks up a student by name and returns their preferred seat info for the class.
+// FindStudentPreferredSeat looks up the student a name typed at join refers to, as resolveStudentName does,
+// and returns their preferred seat info for the class. studentID picks between students with the same name.
 // If the student is not registered, returns (nil, nil, nil) to indicate a guest. Does not create a student.
-func FindStudentPreferredSeat(db *gorm.DB, studentName string, classID string) (*model.Student, *model.StudentPreferredSeat, error) {
-	var student *model.Student
-	var preferredSeat *model.StudentPreferredSeat
+func FindStudentPreferredSeat(db *gorm.DB, class *model.Class, studentName string, studentID *uint) (*model.Student, *model.StudentPreferredSeat, error) {
+	student, err := resolveStudentName(db, class, studentName, studentID)
+	if err != nil || student == nil {
+		// Student not registered: treat as guest (do not create student)
+		return nil, nil, err
+	}
 
-	err := db.Transaction(func(tx *gorm.DB) error {
-		// Try to find the student
-		var err error
-		student, err = GetStudentByName(tx, studentName)
+	// Check if student has a preferred seat in this class (using internal class ID)
+	preferredSeat, err := GetPreferredSeatByStudentAndClass(db, student.ID, class.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Enrolled elsewhere only: no preferred seat here
+		return student, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return student, preferredSeat, nil
+}
+
+// Public IDs are printed under the QR code and typed by students, so the alphabet
+// leaves out characters that are easily confused (0/O, 1/I).
+const (
+	publicIDAlphabet     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	publicIDLength       = 8
+	maxPublicIDAttempts  = 10
+	maxClassNameLength   = 255
+	defaultClassCapacity = 30
+)
+
+var (
+	// ErrInvalidClassName is returned when a class name is empty or too long.
+	ErrInvalidClassName = errors.New("class name must be between 1 and 255 characters")
+	// ErrInvalidCapacity mirrors the chk_capacity_positive constraint.
+	ErrInvalidCapacity = errors.New("total capacity must be greater than zero")
+	// ErrCapacityBelowEnrollment mirrors the chk_student_count_valid constraint.
+	ErrCapacityBelowEnrollment = errors.New("total capacity cannot be less than the number of enrolled students")
+	// ErrInvalidAutoLock mirrors the chk_auto_lock_minutes_non_negative constraint.
+	ErrInvalidAutoLock = errors.New("auto-lock minutes cannot be negative")
+	// ErrPublicIDExhausted is returned when no unused public ID could be generated.
+	ErrPublicIDExhausted = errors.New("could not generate a unique class public ID")
+)
+
+// GeneratePublicID returns a random public class identifier such as "X58E9647".
+func GeneratePublicID() (string, error) {
+	return randomString(publicIDAlphabet, publicIDLength)
+}
+
+func randomString(alphabet string, length int) (string, error) {
+	buf := make([]byte, length)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = alphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
+
+// validateClass checks the class fields against the classes table constraints.
+func validateClass(class *model.Class) error {
+	if class.Name == "" || utf8.RuneCountInString(class.Name) > maxClassNameLength {
+		return ErrInvalidClassName
+	}
+	if class.TotalCapacity <= 0 {
+		return ErrInvalidCapacity
+	}
+	if class.TotalCapacity < class.StudentCount {
+		return ErrCapacityBelowEnrollment
+	}
+	if class.AutoLockMinutes < 0 {
+		return ErrInvalidAutoLock
+	}
+	return nil
+}
+
+// translateClassError maps classes table constraint violations to service errors.
+func translateClassError(err error) error {
+	switch constraintViolation(err) {
+	case "chk_capacity_positive":
+		return ErrInvalidCapacity
+	case "chk_student_count_valid":
+		return ErrCapacityBelowEnrollment
+	case "chk_auto_lock_minutes_non_negative":
+		return ErrInvalidAutoLock
+	}
+	return err
+}
+
+// CreateClass validates and inserts a new class owned by ownerID, generating its internal and public IDs.
+// Public ID generation retries on collision, including collisions with concurrent inserts.
+func CreateClass(db *gorm.DB, ownerID uint, req model.CreateClassRequest) (*model.Class, error) {
+	class := &model.Class{
+		OwnerID:       &ownerID,
+		Name:          strings.TrimSpace(req.Name),
+		TotalCapacity: defaultClassCapacity,
+		IsActive:      true,
+	}
+	if req.TotalCapacity != nil {
+		class.TotalCapacity = *req.TotalCapacity
+	}
+	if req.IsActive != nil {
+		class.IsActive = *req.IsActive
+	}
+	if req.WaitlistWhenFull != nil {
+		class.WaitlistWhenFull = *req.WaitlistWhenFull
+	}
+	if req.RequireApproval != nil {
+		class.RequireApproval = *req.RequireApproval
+	}
+	if req.AutoLockMinutes != nil {
+		class.AutoLockMinutes = *req.AutoLockMinutes
+	}
+	if err := validateClass(class); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < maxPublicIDAttempts; attempt++ {
+		publicID, err := GeneratePublicID()
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				// Student not registered: treat as guest (do not create student)
-				student = nil
-				preferredSeat = nil
+			return nil, err
+		}
+
+		var existing int64
+		if err := db.Model(&model.Class{}).Where("public_id = ?", publicID).Count(&existing).Error; err != nil {
+			return nil, err
+		}
+		if existing > 0 {
+			continue
+		}
+
+		suffix, err := randomString("abcdefghijklmnopqrstuvwxyz0123456789", 12)
+		if err != nil {
+			return nil, err
+		}
+		class.ID = "class-" + suffix
+		class.PublicID = publicID
+
+		isActive := class.IsActive
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(class).Error; err != nil {
+				return err
+			}
+			if isActive {
 				return nil
 			}
+			// GORM replaces a zero-value is_active with the column default on insert
+			class.IsActive = false
+			return tx.Model(class).Update("is_active", false).Error
+		})
+		if err == nil {
+			return class, nil
+		}
+		if constraintViolation(err) == "classes_public_id_key" {
+			// Another request claimed the same public ID between the check and the insert
+			continue
+		}
+		return nil, translateClassError(err)
+	}
+	return nil, ErrPublicIDExhausted
+}
+
+// UpdateClass applies the non-nil fields of req to a class.
+// A class without a saved seating layout has its seats follow its capacity, so a new capacity
+// fails with ErrLayoutExcludesEnrolledSeat if it would drop a seat an enrolled student prefers.
+func UpdateClass(db *gorm.DB, classID string, req model.UpdateClassRequest) (*model.Class, error) {
+	var class model.Class
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Lock the class row so student_count cannot change while capacity is validated
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", classID).First(&class).Error; err != nil {
 			return err
 		}
 
-		// Fetch the class to get its internal ID
-		class, err := GetClassByPublicID(tx, classID)
-		if err != nil {
+		if req.Name != nil {
+			class.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.TotalCapacity != nil {
+			class.TotalCapacity = *req.TotalCapacity
+		}
+		if req.IsActive != nil {
+			class.IsActive = *req.IsActive
+		}
+		if req.WaitlistWhenFull != nil {
+			class.WaitlistWhenFull = *req.WaitlistWhenFull
+		}
+		if req.RequireApproval != nil {
+			class.RequireApproval = *req.RequireApproval
+		}
+		if req.AutoLockMinutes != nil {
+			class.AutoLockMinutes = *req.AutoLockMinutes
+		}
+		if err := validateClass(&class); err != nil {
 			return err
 		}
+		if req.TotalCapacity != nil {
+			var saved int64
+			if err := tx.Model(&model.SeatingLayout{}).Where("class_id = ?", class.ID).Count(&saved).Error; err != nil {
+				return err
+			}
+			if saved == 0 {
+				if err := checkEnrolledSeats(tx, class.ID, DefaultSeatingLayout(&class)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Model(&class).Select("name", "total_capacity", "is_active", "waitlist_when_full", "require_approval", "auto_lock_minutes").Updates(&class).Error
+	})
+	if err != nil {
+		return nil, translateClassError(err)
+	}
+	return &class, nil
+}
 
-		// Check if student has a preferred seat in this class (using internal class ID)
-		preferredSeat, err = GetPreferredSeatByStudentAndClass(tx, student.ID, class.ID)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+// ArchiveClass deactivates a class, hides it from the class list and ends its open session.
+func ArchiveClass(db *gorm.DB, classID string) (*model.Class, error) {
+	var class model.Class
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", classID).First(&class).Error; err != nil {
 			return err
 		}
-		// If err is ErrRecordNotFound, preferredSeat will be nil (no preferred seat)
 
-		return nil
+		now := time.Now()
+		class.IsActive = false
+		if class.ArchivedAt == nil {
+			class.ArchivedAt = &now
+		}
+		if err := tx.Model(&class).Select("is_active", "archived_at").Updates(&class).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.ClassSession{}).
+			Where("class_id = ? AND status <> ?", classID, model.SessionStatusEnded).
+			Updates(map[string]interface{}{
+				"status":    model.SessionStatusEnded,
+				"paused_at": nil,
+				"ended_at":  now,
+			}).Error
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
 
-	return student, preferredSeat, err
+// DeleteClass permanently removes a class; enrollments, sessions and ledgers cascade.
+func DeleteClass(db *gorm.DB, classID string) error {
+	result := db.Where("id = ?", classID).Delete(&model.Class{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
@@ -1,6 +1,8 @@
 package service_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -44,9 +46,10 @@ func TestGetClassByPublicID(t *testing.T) {
 
 func TestGetClasses(t *testing.T) {
 	db, mock := setupMockDB(t)
-	mock.ExpectQuery(`SELECT \* FROM "classes"`).
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE owner_id = \$1 AND archived_at IS NULL`).
+		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name"}).AddRow("class-1", "PUB1", "Test Class").AddRow("class-2", "PUB2", "Other Class"))
-	classes, err := service.GetClasses(db)
+	classes, err := service.GetClasses(db, 1)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -61,10 +64,10 @@ func TestGetClasses(t *testing.T) {
 func TestGetStudentByName(t *testing.T) {
 	db, mock := setupMockDB(t)
 	name := "Alice"
-	mock.ExpectQuery(`SELECT \* FROM "students" WHERE name = \$1 ORDER BY "students"\."id" LIMIT (\$\d+|1)`).
-		WithArgs(name, 1).
+	mock.ExpectQuery(`SELECT \* FROM "students" WHERE name_key = \$1 ORDER BY id LIMIT \$2`).
+		WithArgs("alice", 2).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, name))
-	student, err := service.GetStudentByName(db, name)
+	student, err := service.GetStudentByName(db, " alice ")
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -81,16 +84,9 @@ func TestFindStudentPreferredSeat_Guest(t *testing.T) {
 	class := &model.Class{ID: "class-guest", PublicID: "PUBGUEST", Name: "Guest Class"}
 	guestName := "GuestName"
 
-	// Expect transaction begin
-	mock.ExpectBegin()
-	// Expect student lookup (not found) - this should return ErrRecordNotFound
-	mock.ExpectQuery(`SELECT \* FROM "students" WHERE name = \$1 ORDER BY "students"."id" LIMIT (\$\d+|1)`).
-		WithArgs(guestName, 1).
-		WillReturnError(gorm.ErrRecordNotFound)
-	// When student is not found, transaction should commit immediately (no class lookup needed)
-	mock.ExpectCommit()
+	expectNoStudentNamed(mock, class.ID, "guestname")
 
-	student, seat, err := service.FindStudentPreferredSeat(db, guestName, class.PublicID)
+	student, seat, err := service.FindStudentPreferredSeat(db, class, guestName, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -101,3 +97,147 @@ func TestFindStudentPreferredSeat_Guest(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 }
+
+func TestFindStudentPreferredSeat_FirstNameOnly(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", Name: "Test Class"}
+
+	// Only the whole name is looked up, so "James" never resolves to an enrolled James Smith
+	expectNoStudentNamed(mock, class.ID, "james")
+
+	student, seat, err := service.FindStudentPreferredSeat(db, class, "James", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if student != nil || seat != nil {
+		t.Errorf("expected a partial name to join as a guest, got: %v, %v", student, seat)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGeneratePublicID(t *testing.T) {
+	publicID, err := service.GeneratePublicID()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(publicID) != 8 {
+		t.Errorf("expected 8 character public ID, got %q", publicID)
+	}
+	for _, r := range publicID {
+		if !strings.ContainsRune("ABCDEFGHJKLMNPQRSTUVWXYZ23456789", r) {
+			t.Errorf("unexpected character %q in public ID %q", r, publicID)
+		}
+	}
+}
+
+func TestCreateClass_Validation(t *testing.T) {
+	db, mock := setupMockDB(t)
+	zero := 0
+
+	if _, err := service.CreateClass(db, 1, model.CreateClassRequest{Name: "   "}); !errors.Is(err, service.ErrInvalidClassName) {
+		t.Errorf("expected ErrInvalidClassName, got %v", err)
+	}
+	if _, err := service.CreateClass(db, 1, model.CreateClassRequest{Name: "302 Science", TotalCapacity: &zero}); !errors.Is(err, service.ErrInvalidCapacity) {
+		t.Errorf("expected ErrInvalidCapacity, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCreateClass_RetriesPublicIDCollision(t *testing.T) {
+	db, mock := setupMockDB(t)
+	capacity := 25
+	inactive := false
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "classes" WHERE public_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "classes" WHERE public_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "classes"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "classes" SET "is_active"=\$1,"updated_at"=\$2 WHERE "id" = \$3`).
+		WithArgs(false, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	class, err := service.CreateClass(db, 1, model.CreateClassRequest{Name: " 302 Science ", TotalCapacity: &capacity, IsActive: &inactive})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if class.Name != "302 Science" || class.TotalCapacity != 25 || class.IsActive || class.OwnerID == nil || *class.OwnerID != 1 {
+		t.Errorf("unexpected class: %+v", class)
+	}
+	if len(class.PublicID) != 8 || !strings.HasPrefix(class.ID, "class-") {
+		t.Errorf("expected generated IDs, got id=%q publicId=%q", class.ID, class.PublicID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUpdateClass_CapacityBelowEnrollment(t *testing.T) {
+	db, mock := setupMockDB(t)
+	capacity := 10
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE id = \$1 ORDER BY "classes"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "student_count", "total_capacity", "is_active"}).
+			AddRow("class-1", "PUB1", "Test Class", 12, 30, true))
+	mock.ExpectRollback()
+
+	_, err := service.UpdateClass(db, "class-1", model.UpdateClassRequest{TotalCapacity: &capacity})
+	if !errors.Is(err, service.ErrCapacityBelowEnrollment) {
+		t.Errorf("expected ErrCapacityBelowEnrollment, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUpdateClass_CapacityDropsEnrolledSeat(t *testing.T) {
+	db, mock := setupMockDB(t)
+	capacity := 20
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE id = \$1 ORDER BY "classes"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "student_count", "total_capacity", "is_active"}).
+			AddRow("class-1", "PUB1", "Test Class", 2, 30, true))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "seating_layouts" WHERE class_id = \$1`).
+		WithArgs("class-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT "preferred_seat_number" FROM "student_preferred_seats" WHERE class_id = \$1 ORDER BY preferred_seat_number`).
+		WithArgs("class-1").
+		WillReturnRows(sqlmock.NewRows([]string{"preferred_seat_number"}).AddRow(3).AddRow(25))
+	mock.ExpectRollback()
+
+	_, err := service.UpdateClass(db, "class-1", model.UpdateClassRequest{TotalCapacity: &capacity})
+	if !errors.Is(err, service.ErrLayoutExcludesEnrolledSeat) {
+		t.Errorf("expected ErrLayoutExcludesEnrolledSeat, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDeleteClass_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "classes" WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := service.DeleteClass(db, "missing"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/pkg/logger"
+)
+
+// errForbiddenCommand is returned when the connected teacher may not run a command on the class.
+var errForbiddenCommand = errors.New("you do not have access to this class")
+
+// clientCommand describes how a client-to-server message is decoded, authorized and applied.
+type clientCommand struct {
+	newPayload func() model.ClientPayload
+	// ownerOnly restricts the command to the teacher who owns the class
+	ownerOnly bool
+	// run applies the command and returns the result to acknowledge it with. State changes it makes
+	// are broadcast to every dashboard of the class by the service functions it calls.
+	// A nil run answers with a pong instead of an ack.
+	run func(db *gorm.DB, class *model.Class, client *model.Client, payload model.ClientPayload) (interface{}, error)
+}
+
+// clientCommands lists every client-to-server message type.
+var clientCommands = map[string]clientCommand{
+	model.ClientMessagePing: {
+		newPayload: func() model.ClientPayload { return &model.PingPayload{} },
+	},
+	model.ClientMessageAwardPoints: {
+		newPayload: func() model.ClientPayload { return &model.PointsCommand{} },
+		ownerOnly:  true,
+		run:        pointsCommand(1),
+	},
+	model.ClientMessageDeductPoints: {
+		newPayload: func() model.ClientPayload { return &model.PointsCommand{} },
+		ownerOnly:  true,
+		run:        pointsCommand(-1),
+	},
+	model.ClientMessageMoveStudent: {
+		newPayload: func() model.ClientPayload { return &model.MoveStudentCommand{} },
+		ownerOnly:  true,
+		run: func(db *gorm.DB, class *model.Class, client *model.Client, payload model.ClientPayload) (interface{}, error) {
+			return MoveStudentSeat(db, class, *payload.(*model.MoveStudentCommand))
+		},
+	},
+	model.ClientMessageRemoveStudent: {
+		newPayload: func() model.ClientPayload { return &model.RemoveStudentCommand{} },
+		ownerOnly:  true,
+		run: func(db *gorm.DB, class *model.Class, client *model.Client, payload model.ClientPayload) (interface{}, error) {
+			return RemoveStudent(db, class, *payload.(*model.RemoveStudentCommand))
+		},
+	},
+	model.ClientMessageRegroup: {
+		newPayload: func() model.ClientPayload { return &model.GroupingRequest{} },
+		ownerOnly:  true,
+		run: func(db *gorm.DB, class *model.Class, client *model.Client, payload model.ClientPayload) (interface{}, error) {
+			return FormGroups(db, class, *payload.(*model.GroupingRequest))
+		},
+	},
+	model.ClientMessageApproveJoin: {
+		newPayload: func() model.ClientPayload { return &model.JoinDecisionCommand{} },
+		ownerOnly:  true,
+		run:        joinDecisionCommand(true),
+	},
+	model.ClientMessageRejectJoin: {
+		newPayload: func() model.ClientPayload { return &model.JoinDecisionCommand{} },
+		ownerOnly:  true,
+		run:        joinDecisionCommand(false),
+	},
+}
+
+// joinDecisionCommand returns the run function of a command that approves or rejects a join request.
+func joinDecisionCommand(approve bool) func(*gorm.DB, *model.Class, *model.Client, model.ClientPayload) (interface{}, error) {
+	return func(db *gorm.DB, class *model.Class, client *model.Client, payload model.ClientPayload) (interface{}, error) {
+		return DecideJoinRequest(db, class, payload.(*model.JoinDecisionCommand).RequestID, approve)
+	}
+}
+
+// pointsCommand returns the run function of a points command that records points multiplied by sign
+// under the teacher who sent it.
+func pointsCommand(sign int) func(*gorm.DB, *model.Class, *model.Client, model.ClientPayload) (interface{}, error) {
+	return func(db *gorm.DB, class *model.Class, client *model.Client, payload model.ClientPayload) (interface{}, error) {
+		cmd := payload.(*model.PointsCommand)
+		event, total, err := RecordSessionPoints(db, class, model.PointRequest{
+			StudentID:  cmd.StudentID,
+			SeatNumber: cmd.SeatNumber,
+			Points:     cmd.Points,
+			Reason:     cmd.Reason,
+		}, client.TeacherEmail, sign)
+		if err != nil {
+			return nil, err
+		}
+		return model.PointUpdateResponse{Event: *event, Total: total}, nil
+	}
+}
+
+// HandleClientMessage validates, authorizes and applies a message received from a dashboard.
+// Applied commands are acknowledged with their result; anything else is answered with an error event.
+// Both replies carry the message's request ID and go only to the sending client.
+func HandleClientMessage(db *gorm.DB, client *model.Client, raw []byte) {
+	message, payload, err := ParseClientMessage(raw)
+	requestID := ""
+	if message != nil {
+		requestID = message.RequestID
+	}
+	if err != nil {
+		logger.Infof("Rejected WebSocket message from client in class %s: %v", client.ClassID, err)
+		SendEvent(client, model.ErrorEvent{RequestID: requestID, Code: model.ErrorCodeInvalidMessage, Message: err.Error()})
+		return
+	}
+
+	result, err := runClientCommand(db, client, message.Type, payload)
+	if err != nil {
+		code := commandErrorCode(err)
+		if code == model.ErrorCodeInternal {
+			logger.Errorf("Failed to run %s command for class %s: %v", message.Type, client.ClassID, err)
+		}
+		SendEvent(client, model.ErrorEvent{RequestID: requestID, Code: code, Message: err.Error()})
+		return
+	}
+	if clientCommands[message.Type].run == nil {
+		SendEvent(client, model.PongEvent{RequestID: requestID})
+		return
+	}
+	SendEvent(client, model.AckEvent{RequestID: requestID, Result: result})
+}
+
+// runClientCommand authorizes a validated command against its class and applies it.
+func runClientCommand(db *gorm.DB, client *model.Client, messageType string, payload model.ClientPayload) (interface{}, error) {
+	command := clientCommands[messageType]
+	if command.run == nil {
+		return nil, nil
+	}
+
+	class, err := GetClassByPublicID(db, client.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	if command.ownerOnly && !IsClassOwner(class, client.TeacherID) {
+		return nil, errForbiddenCommand
+	}
+	return command.run(db, class, client, payload)
+}
+
+// commandErrorCode maps a command failure to the error code sent to the client.
+func commandErrorCode(err error) string {
+	switch {
+	case errors.Is(err, errForbiddenCommand):
+		return model.ErrorCodeForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrNotInSession):
+		return model.ErrorCodeNotFound
+	case errors.Is(err, ErrNoActiveSession), errors.Is(err, ErrInsufficientPoints), errors.Is(err, ErrSeatOccupied),
+		errors.Is(err, ErrNoStudentsToGroup), errors.As(err, new(*PairingConstraintsError)),
+		errors.Is(err, ErrJoinRequestDecided), errors.Is(err, ErrClassFull), errors.Is(err, ErrClassInactive),
+		errors.Is(err, ErrClassLocked), errors.As(err, new(*WaitlistedError)), errors.Is(err, ErrAmbiguousName):
+		return model.ErrorCodeConflict
+	case errors.Is(err, ErrInvalidPointTarget), errors.Is(err, ErrInvalidSeatNumber),
+		errors.Is(err, ErrInvalidGrouping), errors.Is(err, ErrUnknownGroupingStrategy):
+		return model.ErrorCodeInvalidMessage
+	default:
+		return model.ErrorCodeInternal
+	}
+}
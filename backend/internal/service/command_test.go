@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+// replyConn is a model.Conn that keeps every message written to it.
+type replyConn struct {
+	mutex    sync.Mutex
+	messages []model.WebSocketMessage
+}
+
+func (c *replyConn) WriteMessage(messageType int, data []byte) error {
+	var message model.WebSocketMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	c.messages = append(c.messages, message)
+	c.mutex.Unlock()
+	return nil
+}
+
+func (c *replyConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func (c *replyConn) Close() error { return nil }
+
+// waitForMessage returns the first message of the given type written to the connection.
+func (c *replyConn) waitForMessage(t *testing.T, messageType string) model.WebSocketMessage {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mutex.Lock()
+		for _, message := range c.messages {
+			if message.Type == messageType {
+				c.mutex.Unlock()
+				return message
+			}
+		}
+		c.mutex.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s message", messageType)
+	return model.WebSocketMessage{}
+}
+
+// connectClient registers a client for a class with a fresh hub and waits until it can receive replies.
+func connectClient(t *testing.T, classID string, teacherID uint) (*model.Client, *replyConn) {
+	t.Helper()
+	if err := service.InitWebSocketHub(); err != nil {
+		t.Fatalf("failed to init hub: %v", err)
+	}
+	conn := &replyConn{}
+	client := &model.Client{Conn: conn, ClassID: classID, TeacherID: teacherID}
+	service.RegisterClient(client)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for service.GetWebSocketStats().Connections == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for client to register")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return client, conn
+}
+
+func expectClassLookup(mock sqlmock.Sqlmock, ownerID uint) {
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1 ORDER BY "classes"\."id" LIMIT (\$\d+|1)`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "total_capacity", "owner_id"}).
+			AddRow("class-1", "PUB1", "Test Class", 30, ownerID))
+}
+
+func TestHandleClientMessage_Ping(t *testing.T) {
+	db, _ := setupMockDB(t)
+	client, conn := connectClient(t, "PUB1", 1)
+
+	service.HandleClientMessage(db, client, []byte(`{"version": 1, "type": "ping", "requestId": "r1"}`))
+
+	reply := conn.waitForMessage(t, model.EventPong)
+	if data := reply.Data.(map[string]interface{}); data["requestId"] != "r1" {
+		t.Errorf("expected pong for r1, got %v", data)
+	}
+}
+
+func TestHandleClientMessage_Invalid(t *testing.T) {
+	db, _ := setupMockDB(t)
+	client, conn := connectClient(t, "PUB1", 1)
+
+	service.HandleClientMessage(db, client, []byte(`{"version": 1, "type": "award_points", "requestId": "r2", "data": {"points": 0}}`))
+
+	reply := conn.waitForMessage(t, model.EventError)
+	data := reply.Data.(map[string]interface{})
+	if data["requestId"] != "r2" || data["code"] != model.ErrorCodeInvalidMessage {
+		t.Errorf("expected invalid_message error for r2, got %v", data)
+	}
+}
+
+func TestHandleClientMessage_Forbidden(t *testing.T) {
+	db, mock := setupMockDB(t)
+	client, conn := connectClient(t, "PUB1", 8)
+	expectClassLookup(mock, 7)
+
+	service.HandleClientMessage(db, client, []byte(`{"version": 1, "type": "award_points", "requestId": "r3", "data": {"studentId": 5, "points": 1}}`))
+
+	reply := conn.waitForMessage(t, model.EventError)
+	data := reply.Data.(map[string]interface{})
+	if data["requestId"] != "r3" || data["code"] != model.ErrorCodeForbidden {
+		t.Errorf("expected forbidden error for r3, got %v", data)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHandleClientMessage_AwardPoints(t *testing.T) {
+	db, mock := setupMockDB(t)
+	client, conn := connectClient(t, "PUB1", 7)
+	client.TeacherEmail = "teacher@example.com"
+	expectClassLookup(mock, 7)
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE id = \$1 AND class_id = \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs(uint(3), "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1 AND student_id = \$2 AND left_at IS NULL`).
+		WithArgs(uint(3), uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(delta\), 0\) FROM "point_events" WHERE session_id = \$1 AND student_id = \$2`).
+		WithArgs(uint(3), uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO "point_events"`).
+		WithArgs("class-1", uint(3), uint(5), nil, nil, 2, "", "teacher@example.com", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectCommit()
+
+	service.HandleClientMessage(db, client, []byte(`{"version": 1, "type": "award_points", "requestId": "r4", "data": {"studentId": 5, "points": 2}}`))
+
+	ack := conn.waitForMessage(t, model.EventAck)
+	if data := ack.Data.(map[string]interface{}); data["requestId"] != "r4" {
+		t.Errorf("expected ack for r4, got %v", data)
+	}
+	changed := conn.waitForMessage(t, model.EventPointsChanged)
+	if data := changed.Data.(map[string]interface{}); data["total"] != float64(3) {
+		t.Errorf("expected broadcast total 3, got %v", data)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
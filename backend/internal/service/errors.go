@@ -0,0 +1,23 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes for constraint violations.
+const (
+	pgUniqueViolation = "23505"
+	pgCheckViolation  = "23514"
+)
+
+// constraintViolation returns the name of the database constraint violated by err,
+// or an empty string if err is not a unique or check constraint violation.
+func constraintViolation(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == pgUniqueViolation || pgErr.Code == pgCheckViolation) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+var (
+	// ErrInvalidGrouping is wrapped by errors describing an invalid grouping request.
+	ErrInvalidGrouping = errors.New("invalid grouping request")
+	// ErrUnknownGroupingStrategy is returned when a grouping request names a strategy that is not registered.
+	ErrUnknownGroupingStrategy = errors.New("unknown grouping strategy")
+	// ErrNoStudentsToGroup is returned when forming groups for a session with nobody to place in them.
+	ErrNoStudentsToGroup = errors.New("there are no students to group")
+)
+
+// GroupCandidate is a student or guest to be placed in a group, with the details strategies may order by.
+type GroupCandidate struct {
+	Member  model.GroupMember
+	Points  int
+	IsGuest bool
+}
+
+// GroupingStrategy distributes candidates into groups of the given sizes, which add up to len(candidates).
+// Candidates are passed enrolled students first in seat order, then guests in seat order.
+// History holds how often enrolled students were grouped together in the class's other sessions.
+type GroupingStrategy interface {
+	Assign(candidates []GroupCandidate, sizes []int, history PairHistory) [][]GroupCandidate
+}
+
+// groupingStrategies maps strategy names to their implementation.
+var groupingStrategies = map[string]GroupingStrategy{
+	model.GroupingSequential: sequentialGrouping{},
+	model.GroupingRandom:     randomGrouping{shuffle: rand.Shuffle},
+	model.GroupingBalanced:   balancedGrouping{},
+	model.GroupingFreshMix:   freshMixGrouping{shuffle: rand.Shuffle},
+}
+
+// RegisterGroupingStrategy makes a strategy available to grouping requests under name,
+// replacing any strategy registered with it. It must be called before the server starts.
+func RegisterGroupingStrategy(name string, strategy GroupingStrategy) {
+	groupingStrategies[name] = strategy
+}
+
+// sequentialGrouping fills groups in the order candidates are given.
+type sequentialGrouping struct{}
+
+func (sequentialGrouping) Assign(candidates []GroupCandidate, sizes []int, _ PairHistory) [][]GroupCandidate {
+	return fillGroups(candidates, sizes)
+}
+
+// randomGrouping fills groups after shuffling the candidates.
+type randomGrouping struct {
+	shuffle func(n int, swap func(i, j int))
+}
+
+func (g randomGrouping) Assign(candidates []GroupCandidate, sizes []int, _ PairHistory) [][]GroupCandidate {
+	shuffled := append([]GroupCandidate(nil), candidates...)
+	g.shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	return fillGroups(shuffled, sizes)
+}
+
+// balancedGrouping deals candidates out from the highest point balance down, reversing direction
+// every round, so each group gets a similar mix of high and low scorers.
+type balancedGrouping struct{}
+
+func (balancedGrouping) Assign(candidates []GroupCandidate, sizes []int, _ PairHistory) [][]GroupCandidate {
+	ranked := append([]GroupCandidate(nil), candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Points > ranked[j].Points })
+
+	groups := make([][]GroupCandidate, len(sizes))
+	next := 0
+	for round := 0; next < len(ranked); round++ {
+		for i := range sizes {
+			g := i
+			if round%2 == 1 {
+				g = len(sizes) - 1 - i
+			}
+			if len(groups[g]) < sizes[g] && next < len(ranked) {
+				groups[g] = append(groups[g], ranked[next])
+				next++
+			}
+		}
+	}
+	return groups
+}
+
+// fillGroups splits candidates, in order, into consecutive groups of the given sizes.
+func fillGroups(candidates []GroupCandidate, sizes []int) [][]GroupCandidate {
+	groups := make([][]GroupCandidate, 0, len(sizes))
+	start := 0
+	for _, size := range sizes {
+		groups = append(groups, candidates[start:start+size])
+		start += size
+	}
+	return groups
+}
+
+// groupSizes returns the sizes of the groups n candidates are split into.
+// A fixed count spreads candidates evenly; a fixed size leaves any remainder in a smaller last group.
+func groupSizes(n int, req model.GroupingRequest) []int {
+	if req.GroupCount > 0 {
+		count := min(req.GroupCount, n)
+		sizes := make([]int, count)
+		for i := range sizes {
+			sizes[i] = n / count
+			if i < n%count {
+				sizes[i]++
+			}
+		}
+		return sizes
+	}
+
+	size := req.GroupSize
+	if size == 0 {
+		size = model.DefaultGroupSize
+	}
+	var sizes []int
+	for remaining := n; remaining > 0; remaining -= size {
+		sizes = append(sizes, min(size, remaining))
+	}
+	return sizes
+}
+
+// ListSessionGroups fetches the groups formed for a class session, in order.
+func ListSessionGroups(db *gorm.DB, sessionID uint) ([]model.StudentGroup, error) {
+	groups := []model.StudentGroup{}
+	result := db.Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("session_id = ?", sessionID).
+		Order("position").
+		Find(&groups)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return groups, nil
+}
+
+// FormGroups groups the enrolled students and guests of the class's current session with the requested
+// strategy, adjusted to honor the class's pairing constraints, replaces the session's groups with the
+// result and notifies the class dashboards. Constraints that cannot be honored are reported in a
+// *PairingConstraintsError and nothing is changed.
+func FormGroups(db *gorm.DB, class *model.Class, req model.GroupingRequest) ([]model.StudentGroup, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGrouping, err)
+	}
+	if req.Strategy == "" {
+		req.Strategy = model.GroupingSequential
+	}
+	strategy, ok := groupingStrategies[req.Strategy]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGroupingStrategy, req.Strategy)
+	}
+
+	session, err := GetCurrentSession(db, class.ID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := groupingCandidates(db, class, session.ID, req.PresentOnly)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoStudentsToGroup
+	}
+	constraints, err := ListPairingConstraints(db, class.ID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := GetPairHistory(db, class.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := strategy.Assign(candidates, groupSizes(len(candidates), req), history)
+	assigned, unsatisfied := applyPairingConstraints(assigned, constraints)
+	if len(unsatisfied) > 0 {
+		return nil, &PairingConstraintsError{Unsatisfied: unsatisfied}
+	}
+	groups := make([]model.StudentGroup, 0, len(assigned))
+	for i, members := range assigned {
+		group := model.StudentGroup{
+			SessionID: session.ID,
+			ClassID:   class.ID,
+			Name:      fmt.Sprintf("Group %d", i+1),
+			Position:  i + 1,
+			Strategy:  req.Strategy,
+			Members:   make([]model.GroupMember, 0, len(members)),
+		}
+		for _, candidate := range members {
+			group.Members = append(group.Members, candidate.Member)
+		}
+		groups = append(groups, group)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Lock the session row so concurrent regroups of the session are serialized
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.ClassSession{}, session.ID).Error; err != nil {
+			return err
+		}
+		// Members are removed with their groups by the foreign key cascade
+		if err := tx.Where("session_id = ?", session.ID).Delete(&model.StudentGroup{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&groups).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	BroadcastEvent(class.PublicID, model.GroupChangedEvent{SessionID: session.ID, Groups: groups})
+	return groups, nil
+}
+
+// groupingCandidates lists who can be grouped in a session: the enrolled roster, in their session seat if they
+// have joined and their preferred seat otherwise, followed by the guests who joined, each with their points.
+func groupingCandidates(db *gorm.DB, class *model.Class, sessionID uint, presentOnly bool) ([]GroupCandidate, error) {
+	roster, err := GetClassRoster(db, class.ID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := ListAttending(db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := GetPointTotals(db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	studentPoints, guestPoints := pointBalances(totals)
+
+	present := make(map[uint]model.AttendanceRecord)
+	var guests []GroupCandidate
+	for _, record := range records {
+		if record.StudentID != nil {
+			present[*record.StudentID] = record
+			continue
+		}
+		guests = append(guests, GroupCandidate{
+			Member:  model.GroupMember{Name: record.Name, SeatNumber: record.SeatNumber},
+			Points:  guestPoints[record.SeatNumber],
+			IsGuest: true,
+		})
+	}
+
+	var candidates []GroupCandidate
+	for _, student := range roster {
+		studentID := student.ID
+		seat := student.PreferredSeatNumber
+		record, joined := present[studentID]
+		if !joined && presentOnly {
+			continue
+		}
+		if joined && record.SeatNumber > 0 {
+			seat = record.SeatNumber
+		}
+		candidates = append(candidates, GroupCandidate{
+			Member: model.GroupMember{StudentID: &studentID, Name: student.Name, SeatNumber: seat},
+			Points: studentPoints[studentID],
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Member.SeatNumber < candidates[j].Member.SeatNumber
+	})
+	sort.SliceStable(guests, func(i, j int) bool {
+		return guests[i].Member.SeatNumber < guests[j].Member.SeatNumber
+	})
+	return append(candidates, guests...), nil
+}
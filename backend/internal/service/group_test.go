@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"classswift-backend/internal/model"
+)
+
+// candidatesWithPoints builds candidates in seats 1..n with the given point balances.
+func candidatesWithPoints(points ...int) []GroupCandidate {
+	candidates := make([]GroupCandidate, len(points))
+	for i, p := range points {
+		candidates[i] = GroupCandidate{Member: model.GroupMember{SeatNumber: i + 1}, Points: p}
+	}
+	return candidates
+}
+
+// seats returns the seat numbers of each group's members.
+func seats(groups [][]GroupCandidate) [][]int {
+	result := make([][]int, len(groups))
+	for i, group := range groups {
+		result[i] = []int{}
+		for _, candidate := range group {
+			result[i] = append(result[i], candidate.Member.SeatNumber)
+		}
+	}
+	return result
+}
+
+func TestGroupSizes(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		req  model.GroupingRequest
+		want []int
+	}{
+		{"default size", 12, model.GroupingRequest{}, []int{5, 5, 2}},
+		{"fixed size", 9, model.GroupingRequest{GroupSize: 3}, []int{3, 3, 3}},
+		{"fixed count", 11, model.GroupingRequest{GroupCount: 3}, []int{4, 4, 3}},
+		{"count above students", 2, model.GroupingRequest{GroupCount: 4}, []int{1, 1}},
+	}
+	for _, tt := range tests {
+		if got := groupSizes(tt.n, tt.req); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSequentialGrouping(t *testing.T) {
+	groups := sequentialGrouping{}.Assign(candidatesWithPoints(0, 0, 0, 0, 0), []int{3, 2}, nil)
+
+	if want := [][]int{{1, 2, 3}, {4, 5}}; !reflect.DeepEqual(seats(groups), want) {
+		t.Errorf("expected %v, got %v", want, seats(groups))
+	}
+}
+
+func TestRandomGrouping(t *testing.T) {
+	reverse := func(n int, swap func(i, j int)) {
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	}
+	candidates := candidatesWithPoints(0, 0, 0, 0)
+	groups := randomGrouping{shuffle: reverse}.Assign(candidates, []int{2, 2}, nil)
+
+	if want := [][]int{{4, 3}, {2, 1}}; !reflect.DeepEqual(seats(groups), want) {
+		t.Errorf("expected %v, got %v", want, seats(groups))
+	}
+	if candidates[0].Member.SeatNumber != 1 {
+		t.Error("expected the candidates passed in to be left in order")
+	}
+}
+
+func TestBalancedGrouping(t *testing.T) {
+	groups := balancedGrouping{}.Assign(candidatesWithPoints(10, 1, 8, 3, 5, 6, 0), []int{3, 2, 2}, nil)
+
+	// Ranked seats 1,3,6,5,4,2,7 are dealt 0,1,2 then 2,1,0 then 0
+	if want := [][]int{{1, 2, 7}, {3, 4}, {6, 5}}; !reflect.DeepEqual(seats(groups), want) {
+		t.Errorf("expected %v, got %v", want, seats(groups))
+	}
+}
+
+func TestFormGroups_InvalidRequest(t *testing.T) {
+	class := &model.Class{ID: "class-1", PublicID: "PUB1"}
+
+	_, err := FormGroups(nil, class, model.GroupingRequest{GroupSize: 4, GroupCount: 2})
+	if !errors.Is(err, ErrInvalidGrouping) {
+		t.Errorf("expected ErrInvalidGrouping, got %v", err)
+	}
+	_, err = FormGroups(nil, class, model.GroupingRequest{Strategy: "alphabetical"})
+	if !errors.Is(err, ErrUnknownGroupingStrategy) {
+		t.Errorf("expected ErrUnknownGroupingStrategy, got %v", err)
+	}
+}
+
+// enrolledCandidates builds candidates for students 1..n in seats 1..n.
+func enrolledCandidates(n int) []GroupCandidate {
+	candidates := make([]GroupCandidate, n)
+	for i := range candidates {
+		id := uint(i + 1)
+		candidates[i] = GroupCandidate{Member: model.GroupMember{StudentID: &id, Name: fmt.Sprintf("S%d", id), SeatNumber: i + 1}}
+	}
+	return candidates
+}
+
+func pairing(kind string, a, b uint) model.PairingConstraint {
+	return model.PairingConstraint{Kind: kind, StudentAID: a, StudentBID: b}
+}
+
+func TestApplyPairingConstraints(t *testing.T) {
+	groups := fillGroups(enrolledCandidates(6), []int{3, 3})
+	constraints := []model.PairingConstraint{
+		pairing(model.PairingKeepApart, 1, 2),
+		pairing(model.PairingKeepTogether, 3, 4),
+		// Student 9 is not being grouped, so this rule is ignored
+		pairing(model.PairingKeepApart, 5, 9),
+	}
+
+	placed, unsatisfied := applyPairingConstraints(groups, constraints)
+	if len(unsatisfied) != 0 {
+		t.Fatalf("expected every constraint to be honored, got %+v", unsatisfied)
+	}
+	groupOf := make(map[int]int)
+	for g, group := range seats(placed) {
+		if len(group) != 3 {
+			t.Errorf("expected group %d to keep 3 members, got %v", g, group)
+		}
+		for _, seat := range group {
+			groupOf[seat] = g
+		}
+	}
+	if groupOf[1] == groupOf[2] {
+		t.Errorf("expected students 1 and 2 apart, got %v", seats(placed))
+	}
+	if groupOf[3] != groupOf[4] {
+		t.Errorf("expected students 3 and 4 together, got %v", seats(placed))
+	}
+}
+
+func TestApplyPairingConstraints_StudentsNotGrouped(t *testing.T) {
+	groups := fillGroups(enrolledCandidates(4), []int{2, 2})
+
+	placed, unsatisfied := applyPairingConstraints(groups, []model.PairingConstraint{pairing(model.PairingKeepApart, 7, 8)})
+	if len(unsatisfied) != 0 || !reflect.DeepEqual(seats(placed), [][]int{{1, 2}, {3, 4}}) {
+		t.Errorf("expected groups unchanged, got %v %+v", seats(placed), unsatisfied)
+	}
+}
+
+func TestApplyPairingConstraints_Unsatisfiable(t *testing.T) {
+	tests := []struct {
+		name        string
+		sizes       []int
+		constraints []model.PairingConstraint
+		want        int
+	}{
+		{
+			name:  "together larger than a group",
+			sizes: []int{2, 2},
+			constraints: []model.PairingConstraint{
+				pairing(model.PairingKeepTogether, 1, 2),
+				pairing(model.PairingKeepTogether, 2, 3),
+			},
+			want: 2,
+		},
+		{
+			name:  "apart within a together set",
+			sizes: []int{2, 2},
+			constraints: []model.PairingConstraint{
+				pairing(model.PairingKeepTogether, 1, 2),
+				pairing(model.PairingKeepApart, 1, 2),
+			},
+			want: 1,
+		},
+		{
+			name:  "more mutually apart students than groups",
+			sizes: []int{2, 2},
+			constraints: []model.PairingConstraint{
+				pairing(model.PairingKeepApart, 1, 2),
+				pairing(model.PairingKeepApart, 1, 3),
+				pairing(model.PairingKeepApart, 2, 3),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		placed, unsatisfied := applyPairingConstraints(fillGroups(enrolledCandidates(4), tt.sizes), tt.constraints)
+		if placed != nil || len(unsatisfied) != tt.want {
+			t.Errorf("%s: expected %d unsatisfied constraints, got %+v", tt.name, tt.want, unsatisfied)
+			continue
+		}
+		if unsatisfied[0].Reason == "" || len(unsatisfied[0].Students) != 2 {
+			t.Errorf("%s: expected a reason naming both students, got %+v", tt.name, unsatisfied[0])
+		}
+	}
+}
+
+func TestFreshMixGrouping(t *testing.T) {
+	keepOrder := func(int, func(i, j int)) {}
+	history := PairHistory{{1, 2}: 3, {3, 4}: 2, {1, 3}: 1}
+
+	groups := freshMixGrouping{shuffle: keepOrder}.Assign(enrolledCandidates(4), []int{2, 2}, history)
+
+	for _, group := range groups {
+		if repeats := history.Count(group[0].Member, group[1].Member); repeats != 0 {
+			t.Errorf("expected only never-paired students together, got %v", seats(groups))
+		}
+	}
+}
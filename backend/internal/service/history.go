@@ -0,0 +1,156 @@
+package service
+
+import (
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+)
+
+// maxFreshMixPasses bounds how many times the fresh mix strategy looks over every possible swap.
+const maxFreshMixPasses = 20
+
+// PairHistory counts how many sessions each pair of enrolled students was grouped together in,
+// keyed by the two student IDs in ascending order.
+type PairHistory map[[2]uint]int
+
+// Count returns how often two group members were grouped together before. Guests have no history.
+func (h PairHistory) Count(a, b model.GroupMember) int {
+	if a.StudentID == nil || b.StudentID == nil {
+		return 0
+	}
+	x, y := *a.StudentID, *b.StudentID
+	if x > y {
+		x, y = y, x
+	}
+	return h[[2]uint{x, y}]
+}
+
+// pairCount is a row of the pair co-occurrence query.
+type pairCount struct {
+	StudentAID uint
+	StudentBID uint
+	Count      int
+}
+
+// pairCounts counts, for each pair of enrolled students, the sessions of a class they shared a group in.
+// A session's groups are its grouping history, since regrouping a session replaces them.
+// Rows pairing a student with themselves count the sessions they were grouped in.
+func pairCounts(db *gorm.DB, classID string, excludeSessionID uint) ([]pairCount, error) {
+	var counts []pairCount
+	result := db.Table("student_group_members AS a").
+		Select("a.student_id AS student_a_id, b.student_id AS student_b_id, COUNT(*) AS count").
+		Joins("JOIN student_group_members b ON b.group_id = a.group_id AND a.student_id <= b.student_id").
+		Joins("JOIN student_groups g ON g.id = a.group_id").
+		Where("g.class_id = ? AND g.session_id <> ?", classID, excludeSessionID).
+		Group("a.student_id, b.student_id").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return counts, nil
+}
+
+// GetPairHistory returns how often each pair of a class's students was grouped together,
+// leaving out the groups of the given session, which is about to be regrouped.
+func GetPairHistory(db *gorm.DB, classID string, excludeSessionID uint) (PairHistory, error) {
+	counts, err := pairCounts(db, classID, excludeSessionID)
+	if err != nil {
+		return nil, err
+	}
+	history := make(PairHistory)
+	for _, count := range counts {
+		if count.StudentAID != count.StudentBID {
+			history[[2]uint{count.StudentAID, count.StudentBID}] = count.Count
+		}
+	}
+	return history, nil
+}
+
+// GetPairingMatrix builds the pair co-occurrence matrix of a class's enrolled students, in roster order.
+func GetPairingMatrix(db *gorm.DB, class *model.Class) (*model.PairingMatrix, error) {
+	roster, err := GetClassRoster(db, class.ID)
+	if err != nil {
+		return nil, err
+	}
+	var sessions int64
+	if err := db.Model(&model.StudentGroup{}).Where("class_id = ?", class.ID).Distinct("session_id").Count(&sessions).Error; err != nil {
+		return nil, err
+	}
+	counts, err := pairCounts(db, class.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &model.PairingMatrix{
+		ClassID:  class.PublicID,
+		Sessions: int(sessions),
+		Students: make([]model.MatrixStudent, len(roster)),
+		Counts:   make([][]int, len(roster)),
+	}
+	index := make(map[uint]int, len(roster))
+	for i, student := range roster {
+		index[student.ID] = i
+		matrix.Students[i] = model.MatrixStudent{ID: student.ID, Name: student.Name}
+		matrix.Counts[i] = make([]int, len(roster))
+	}
+	// Students who have since left the class are not in the matrix
+	for _, count := range counts {
+		i, okA := index[count.StudentAID]
+		j, okB := index[count.StudentBID]
+		if !okA || !okB {
+			continue
+		}
+		matrix.Counts[i][j] = count.Count
+		matrix.Counts[j][i] = count.Count
+	}
+	return matrix, nil
+}
+
+// freshMixGrouping starts from a shuffled grouping, then swaps candidates between groups for as long as
+// a swap lowers how often group members have been grouped together before.
+type freshMixGrouping struct {
+	shuffle func(n int, swap func(i, j int))
+}
+
+func (g freshMixGrouping) Assign(candidates []GroupCandidate, sizes []int, history PairHistory) [][]GroupCandidate {
+	shuffled := append([]GroupCandidate(nil), candidates...)
+	g.shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	groups := fillGroups(shuffled, sizes)
+	if len(history) == 0 {
+		return groups
+	}
+
+	// repeats counts how often c was grouped before with the members of group other than the one at skip
+	repeats := func(c GroupCandidate, group []GroupCandidate, skip int) int {
+		total := 0
+		for k, member := range group {
+			if k != skip {
+				total += history.Count(c.Member, member.Member)
+			}
+		}
+		return total
+	}
+
+	for pass := 0; pass < maxFreshMixPasses; pass++ {
+		improved := false
+		for gi := range groups {
+			for gj := gi + 1; gj < len(groups); gj++ {
+				for i := range groups[gi] {
+					for j := range groups[gj] {
+						x, y := groups[gi][i], groups[gj][j]
+						delta := repeats(y, groups[gi], i) - repeats(x, groups[gi], i) +
+							repeats(x, groups[gj], j) - repeats(y, groups[gj], j)
+						if delta < 0 {
+							groups[gi][i], groups[gj][j] = y, x
+							improved = true
+						}
+					}
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return groups
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestGetPairHistory(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT a.student_id AS student_a_id, b.student_id AS student_b_id, COUNT\(\*\) AS count FROM student_group_members AS a JOIN student_group_members b ON b.group_id = a.group_id AND a.student_id <= b.student_id JOIN student_groups g ON g.id = a.group_id WHERE g.class_id = \$1 AND g.session_id <> \$2 GROUP BY a.student_id, b.student_id`).
+		WithArgs("class-1", uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"student_a_id", "student_b_id", "count"}).
+			AddRow(1, 1, 4).
+			AddRow(1, 2, 2))
+
+	history, err := service.GetPairHistory(db, "class-1", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	one, two := uint(1), uint(2)
+	if got := history.Count(model.GroupMember{StudentID: &two}, model.GroupMember{StudentID: &one}); got != 2 {
+		t.Errorf("expected students 1 and 2 grouped twice, got %d", got)
+	}
+	if got := history.Count(model.GroupMember{StudentID: &one}, model.GroupMember{Name: "Guest"}); got != 0 {
+		t.Errorf("expected no history with a guest, got %d", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetPairingMatrix(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "X58E9647"}
+
+	mock.ExpectQuery(`SELECT s.id, s.name, sps.class_id, sps.preferred_seat_number`).
+		WithArgs("class-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "class_id", "preferred_seat_number"}).
+			AddRow(1, "Philip", "class-1", 1).
+			AddRow(2, "Darrell", "class-1", 2))
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT\("session_id"\)\) FROM "student_groups" WHERE class_id = \$1`).
+		WithArgs("class-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`FROM student_group_members AS a`).
+		WithArgs("class-1", uint(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"student_a_id", "student_b_id", "count"}).
+			AddRow(1, 1, 3).
+			AddRow(1, 2, 2).
+			AddRow(2, 2, 2).
+			AddRow(2, 9, 1))
+
+	matrix, err := service.GetPairingMatrix(db, class)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if matrix.Sessions != 3 || len(matrix.Students) != 2 {
+		t.Errorf("expected 3 sessions and 2 students, got %+v", matrix)
+	}
+	if want := [][]int{{3, 2}, {2, 2}}; !reflect.DeepEqual(matrix.Counts, want) {
+		t.Errorf("expected counts %v, got %v", want, matrix.Counts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+)
+
+var (
+	// ErrClassInactive is returned when joining a class that is not accepting students.
+	ErrClassInactive = errors.New("class is not accepting students")
+	// ErrClassLocked is returned when joining a session the teacher has locked, or that has locked on its own.
+	ErrClassLocked = errors.New("class is locked; no more students can join this session")
+)
+
+// JoinClass resolves a joining student for a class and records the join in the attendance of
+// the class's open session with an assigned seat: the student's preferred seat if it is free,
+// otherwise one chosen by the class's seat policy.
+// A name matching several students of the class fails with an *AmbiguousNameError
+// unless chosenStudentID picks one of them.
+// Joins to an inactive class fail with ErrClassInactive, joins to a class without an open session
+// with ErrNoActiveSession, and new joins to a locked session with ErrClassLocked.
+// Once the session is full, joins fail with ErrClassFull, or a *WaitlistedError if the class wait-lists
+// joiners, and a class_full event is broadcast.
+func JoinClass(db *gorm.DB, classPublicID string, studentName string, chosenStudentID *uint) (*model.JoinResult, error) {
+	var result *model.JoinResult
+	var full *model.ClassFullEvent
+	var refused error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, full, refused, err = joinClass(tx, classPublicID, studentName, chosenStudentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if full != nil {
+		BroadcastEvent(result.Class.PublicID, *full)
+		return nil, refused
+	}
+	return result, nil
+}
+
+// joinClass does the work of JoinClass in tx. A join refused for lack of seats returns the class_full
+// event to broadcast once tx commits, with the refusal in refused; tx is left to commit any waiting
+// list entry made for the joiner.
+func joinClass(tx *gorm.DB, classPublicID string, studentName string, chosenStudentID *uint) (result *model.JoinResult, full *model.ClassFullEvent, refused error, err error) {
+	result = &model.JoinResult{}
+	result.Class, err = GetClassByPublicID(tx, classPublicID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !result.Class.IsActive {
+		return nil, nil, nil, ErrClassInactive
+	}
+
+	result.Student, result.PreferredSeat, err = FindStudentPreferredSeat(tx, result.Class, studentName, chosenStudentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	result.Name = CleanStudentName(studentName)
+	if result.Student != nil {
+		result.Name = result.Student.Name
+	}
+
+	session, err := GetCurrentSession(tx, result.Class.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Lock the session row so concurrent joins cannot be assigned the same seat
+	var locked model.ClassSession
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, session.ID).Error; err != nil {
+		return nil, nil, nil, err
+	}
+	session = &locked
+
+	var studentID *uint
+	if result.Student != nil {
+		studentID = &result.Student.ID
+	}
+	// Joining again keeps the seat of the first join
+	existing, err := findAttendance(tx, session.ID, studentID, result.Name)
+	if err == nil {
+		result.Attendance = existing
+		return result, nil, nil, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, nil, err
+	}
+
+	joinedAt := time.Now()
+	if session.JoinsLockedAt(joinedAt) {
+		return nil, nil, nil, ErrClassLocked
+	}
+	full, err = admitJoin(tx, result.Class, session.ID, studentID, result.Name)
+	if full != nil {
+		// The join is refused, but a waiting list entry is kept
+		return result, full, err, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	record := &model.AttendanceRecord{
+		SessionID: session.ID,
+		ClassID:   result.Class.ID,
+		Name:      result.Name,
+		NameKey:   studentNameKey(result.Name),
+		JoinedAt:  joinedAt,
+		StudentID: studentID,
+		IsLate:    IsLateJoin(session, joinedAt, config.AttendanceLateAfter()),
+	}
+	preferred := 0
+	if result.PreferredSeat != nil {
+		preferred = result.PreferredSeat.PreferredSeatNumber
+	}
+	record.SeatNumber, err = assignSeat(tx, result.Class, session.ID, preferred)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := RecordAttendance(tx, record); err != nil {
+		return nil, nil, nil, err
+	}
+	result.Attendance = record
+	return result, nil, nil, nil
+}
+
+// AnnounceJoin notifies the class dashboards of a completed join.
+func AnnounceJoin(result *model.JoinResult, studentName string) {
+	joined := model.StudentJoinedEvent{
+		Name:    studentName,
+		IsGuest: result.Student == nil,
+	}
+	if result.PreferredSeat != nil {
+		joined.SeatNumber = result.PreferredSeat.PreferredSeatNumber
+	}
+	if result.Student != nil {
+		joined.StudentID = &result.Student.ID
+	}
+	// Add attendance details and the assigned seat
+	if result.Attendance != nil {
+		joined.SeatNumber = result.Attendance.SeatNumber
+		joined.SessionID = &result.Attendance.SessionID
+		joined.IsLate = result.Attendance.IsLate
+	}
+	BroadcastEvent(result.Class.PublicID, joined)
+}
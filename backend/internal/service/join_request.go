@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+// maxJoinDecisionWait bounds how long a joining device may wait for a decision in a single request.
+const maxJoinDecisionWait = 30 * time.Second
+
+// joinDecisionPollInterval is how often a waiting device's join request is reloaded.
+const joinDecisionPollInterval = time.Second
+
+var (
+	// ErrJoinRequestPending is returned when a join request under the same name is already waiting.
+	ErrJoinRequestPending = errors.New("a join request under this name is already waiting for approval")
+	// ErrJoinRequestDecided is returned when approving or rejecting a join request that is no longer pending.
+	ErrJoinRequestDecided = errors.New("join request has already been decided")
+)
+
+// RequestJoin queues a join to a class that requires approval and notifies the class dashboards.
+// Joins to a class without an open session, or to a locked session, are refused without asking the teacher. The name is matched to a student
+// as JoinClass does, so a name shared by several students of the class needs chosenStudentID to be queued.
+func RequestJoin(db *gorm.DB, class *model.Class, studentName string, chosenStudentID *uint) (*model.JoinRequest, error) {
+	if !class.IsActive {
+		return nil, ErrClassInactive
+	}
+	session, err := GetCurrentSession(db, class.ID)
+	if err != nil {
+		return nil, err
+	}
+	if session.JoinsLockedAt(time.Now()) {
+		return nil, ErrClassLocked
+	}
+	pollToken, err := randomString(tokenIDAlphabet, 32)
+	if err != nil {
+		return nil, err
+	}
+	request := &model.JoinRequest{
+		ClassID:   class.ID,
+		Name:      CleanStudentName(studentName),
+		Status:    model.JoinRequestPending,
+		PollToken: pollToken,
+	}
+	student, err := resolveStudentName(db, class, studentName, chosenStudentID)
+	if err != nil {
+		return nil, err
+	}
+	if student != nil {
+		request.StudentID = &student.ID
+		request.Name = student.Name
+	}
+	request.NameKey = studentNameKey(request.Name)
+
+	if err := db.Create(request).Error; err != nil {
+		if constraintViolation(err) == "unique_join_request_pending_name" {
+			return nil, ErrJoinRequestPending
+		}
+		return nil, err
+	}
+	BroadcastEvent(class.PublicID, model.JoinRequestEvent{Type: model.EventJoinRequested, Request: *request})
+	return request, nil
+}
+
+// ListJoinRequests fetches a class's pending join requests, oldest first.
+func ListJoinRequests(db *gorm.DB, classID string) ([]model.JoinRequest, error) {
+	var requests []model.JoinRequest
+	result := db.Where("class_id = ? AND status = ?", classID, model.JoinRequestPending).Order("id").Find(&requests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return requests, nil
+}
+
+// GetJoinRequestByPollToken fetches the join request a joining device follows.
+func GetJoinRequestByPollToken(db *gorm.DB, pollToken string) (*model.JoinRequest, error) {
+	var request model.JoinRequest
+	if err := db.Where("poll_token = ?", pollToken).First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// WaitForJoinDecision returns a join request once it is decided, or as it stands when the wait
+// (at most maxJoinDecisionWait) runs out or ctx is done. A zero wait returns it straight away.
+func WaitForJoinDecision(ctx context.Context, db *gorm.DB, pollToken string, wait time.Duration) (*model.JoinRequest, error) {
+	deadline := time.Now().Add(min(wait, maxJoinDecisionWait))
+	for {
+		request, err := GetJoinRequestByPollToken(db, pollToken)
+		if err != nil || request.Status != model.JoinRequestPending || !time.Now().Before(deadline) {
+			return request, err
+		}
+		select {
+		case <-ctx.Done():
+			return request, nil
+		case <-time.After(joinDecisionPollInterval):
+		}
+	}
+}
+
+// DecideJoinRequest approves or rejects a pending join request of a class and notifies the class dashboards.
+// Approving joins the class as JoinClass does and issues the student's session token; if the join is
+// refused, the request stays pending and the refusal is returned; a joiner put on the waiting list
+// stays on it, and the class_full event is broadcast once that is committed.
+func DecideJoinRequest(db *gorm.DB, class *model.Class, requestID uint, approve bool) (*model.JoinRequest, error) {
+	var request model.JoinRequest
+	var result *model.JoinResult
+	var full *model.ClassFullEvent
+	var refused error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND class_id = ?", requestID, class.ID).
+			First(&request).Error; err != nil {
+			return err
+		}
+		if request.Status != model.JoinRequestPending {
+			return ErrJoinRequestDecided
+		}
+
+		request.Status = model.JoinRequestRejected
+		if approve {
+			var err error
+			result, full, refused, err = joinClass(tx, class.PublicID, request.Name, request.StudentID)
+			if err != nil || full != nil {
+				return err
+			}
+			request.SessionToken, _, err = IssueStudentToken(result, result.Name)
+			if err != nil {
+				return err
+			}
+			request.Status = model.JoinRequestApproved
+		}
+		now := time.Now()
+		request.DecidedAt = &now
+		return tx.Model(&request).Select("status", "session_token", "decided_at").Updates(&request).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if full != nil {
+		BroadcastEvent(class.PublicID, *full)
+		return nil, refused
+	}
+
+	BroadcastEvent(class.PublicID, model.JoinRequestEvent{Type: model.EventJoinDecided, Request: request})
+	if result != nil {
+		AnnounceJoin(result, result.Name)
+	}
+	return &request, nil
+}
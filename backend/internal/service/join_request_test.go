@@ -0,0 +1,276 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestRequestJoin_Inactive(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", RequireApproval: true}
+
+	if _, err := service.RequestJoin(db, class, "Guest", nil); !errors.Is(err, service.ErrClassInactive) {
+		t.Errorf("expected ErrClassInactive, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRequestJoin_NoSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	if _, err := service.RequestJoin(db, class, "Guest", nil); !errors.Is(err, service.ErrNoActiveSession) {
+		t.Errorf("expected ErrNoActiveSession, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRequestJoin_Locked(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status", "joins_locked"}).
+			AddRow(7, "class-1", model.SessionStatusActive, true))
+
+	if _, err := service.RequestJoin(db, class, "Guest", nil); !errors.Is(err, service.ErrClassLocked) {
+		t.Errorf("expected ErrClassLocked, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRequestJoin(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+
+	expectOpenSession(mock)
+	mock.ExpectQuery(`SELECT s\.\*, COALESCE\(sps\.preferred_seat_number, 0\) AS seat_number FROM students AS s`).
+		WithArgs("class-1", "alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "seat_number"}).AddRow(4, "Alice", 2))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "join_requests"`).
+		WithArgs("class-1", uint(4), "Alice", "alice", model.JoinRequestPending, sqlmock.AnyArg(), "", sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectCommit()
+
+	request, err := service.RequestJoin(db, class, "Alice", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.ID != 12 || request.StudentID == nil || *request.StudentID != 4 || len(request.PollToken) != 32 {
+		t.Errorf("unexpected join request: %+v", request)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRequestJoin_StudentOfAnotherClass(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+
+	// The only Alice is not enrolled in the class, so the joiner asks to join as a guest
+	expectOpenSession(mock)
+	mock.ExpectQuery(`SELECT s\.\*, COALESCE\(sps\.preferred_seat_number, 0\) AS seat_number FROM students AS s`).
+		WithArgs("class-1", "alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "seat_number"}).AddRow(4, "Alice", 0))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "join_requests"`).
+		WithArgs("class-1", nil, "Alice", "alice", model.JoinRequestPending, sqlmock.AnyArg(), "", sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(13))
+	mock.ExpectCommit()
+
+	request, err := service.RequestJoin(db, class, "Alice", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.StudentID != nil {
+		t.Errorf("expected a guest join request, got student %d", *request.StudentID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+// expectOpenSession expects the lookup of class-1's current session to find an open, unlocked session.
+func expectOpenSession(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(7, "class-1", model.SessionStatusActive))
+}
+
+// expectTwoJameses expects the lookup of "james" in class-1 to find two enrolled students named James.
+func expectTwoJameses(mock sqlmock.Sqlmock) {
+	expectOpenSession(mock)
+	mock.ExpectQuery(`SELECT s\.\*, COALESCE\(sps\.preferred_seat_number, 0\) AS seat_number FROM students AS s`).
+		WithArgs("class-1", "james").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "seat_number"}).
+			AddRow(4, "James", 3).
+			AddRow(9, "James", 8))
+}
+
+func TestRequestJoin_AmbiguousName(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+	expectTwoJameses(mock)
+
+	_, err := service.RequestJoin(db, class, "james ", nil)
+	var ambiguous *service.AmbiguousNameError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousNameError, got %v", err)
+	}
+	if len(ambiguous.Choices) != 2 || ambiguous.Choices[0].Label != "James (seat 3)" || ambiguous.Choices[1].StudentID != 9 {
+		t.Errorf("unexpected choices: %+v", ambiguous.Choices)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRequestJoin_ChosenStudent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+	chosen := uint(9)
+	expectTwoJameses(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "join_requests"`).
+		WithArgs("class-1", uint(9), "James", "james", model.JoinRequestPending, sqlmock.AnyArg(), "", sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(13))
+	mock.ExpectCommit()
+
+	if _, err := service.RequestJoin(db, class, "james", &chosen); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRequestJoin_AlreadyPending(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+
+	expectOpenSession(mock)
+	expectNoStudentNamed(mock, "class-1", "guest")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "join_requests"`).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "unique_join_request_pending_name"})
+	mock.ExpectRollback()
+
+	if _, err := service.RequestJoin(db, class, "Guest", nil); !errors.Is(err, service.ErrJoinRequestPending) {
+		t.Errorf("expected ErrJoinRequestPending, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDecideJoinRequest_Reject(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "join_requests" WHERE id = \$1 AND class_id = \$2 .* FOR UPDATE`).
+		WithArgs(12, "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "name", "status"}).AddRow(12, "class-1", "Prankster", model.JoinRequestPending))
+	mock.ExpectExec(`UPDATE "join_requests" SET "status"=\$1,"session_token"=\$2,"decided_at"=\$3 WHERE "id" = \$4`).
+		WithArgs(model.JoinRequestRejected, "", sqlmock.AnyArg(), 12).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	request, err := service.DecideJoinRequest(db, class, 12, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.Status != model.JoinRequestRejected || request.DecidedAt == nil {
+		t.Errorf("expected a rejected request, got %+v", request)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDecideJoinRequest_Waitlisted(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true, RequireApproval: true}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "join_requests" WHERE id = \$1 AND class_id = \$2 .* FOR UPDATE`).
+		WithArgs(12, "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "name", "status"}).AddRow(12, "class-1", "Guest", model.JoinRequestPending))
+	expectFullSession(mock, 2, true)
+	mock.ExpectQuery(`INSERT INTO "join_waitlist"`).
+		WithArgs(7, "class-1", nil, "Guest", "guest", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	// The waiting list entry is committed and the request stays pending
+	mock.ExpectCommit()
+
+	_, err := service.DecideJoinRequest(db, class, 12, true)
+	var waitlisted *service.WaitlistedError
+	if !errors.As(err, &waitlisted) || waitlisted.Position != 2 {
+		t.Errorf("expected to be second on the waiting list, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDecideJoinRequest_AlreadyDecided(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", IsActive: true}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "join_requests" WHERE id = \$1 AND class_id = \$2`).
+		WithArgs(12, "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "name", "status"}).AddRow(12, "class-1", "Alice", model.JoinRequestApproved))
+	mock.ExpectRollback()
+
+	if _, err := service.DecideJoinRequest(db, class, 12, true); !errors.Is(err, service.ErrJoinRequestDecided) {
+		t.Errorf("expected ErrJoinRequestDecided, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestWaitForJoinDecision_Decided(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "join_requests" WHERE poll_token = \$1`).
+		WithArgs("poll-token", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "session_token"}).AddRow(12, model.JoinRequestApproved, "signed"))
+
+	// A decided request is returned without waiting
+	request, err := service.WaitForJoinDecision(context.Background(), db, "poll-token", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.Status != model.JoinRequestApproved || request.SessionToken != "signed" {
+		t.Errorf("expected the approved request, got %+v", request)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
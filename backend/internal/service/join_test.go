@@ -0,0 +1,234 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+// expectNoStudentNamed expects the lookup of a joining name, by its key, that finds no student in a class.
+func expectNoStudentNamed(mock sqlmock.Sqlmock, classID, key string) {
+	mock.ExpectQuery(`SELECT s\.\*, COALESCE\(sps\.preferred_seat_number, 0\) AS seat_number FROM students AS s LEFT JOIN student_preferred_seats sps .* WHERE s\.name_key = \$2`).
+		WithArgs(classID, key).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "seat_number"}))
+}
+
+func TestJoinClass_WithoutSession(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "is_active"}).AddRow("class-1", "PUB1", "Test Class", true))
+	expectNoStudentNamed(mock, "class-1", "guest")
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	if _, err := service.JoinClass(db, "PUB1", "Guest", nil); !errors.Is(err, service.ErrNoActiveSession) {
+		t.Errorf("expected ErrNoActiveSession, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestJoinClass_GuestJoinsAgainByNameKey(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "total_capacity", "is_active"}).AddRow("class-1", "PUB1", "Test Class", 10, true))
+	expectNoStudentNamed(mock, "class-1", "guest")
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(7, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE "class_sessions"."id" = \$1 .* FOR UPDATE`).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	// A full-width, upper-case spelling finds the guest's earlier join by its name key
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND \(student_id IS NULL AND name_key = \$2\)`).
+		WithArgs(7, "guest", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "name", "seat_number"}).AddRow(30, 7, "Guest", 5))
+	mock.ExpectCommit()
+
+	result, err := service.JoinClass(db, "PUB1", "ＧＵＥＳＴ", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Attendance == nil || result.Attendance.ID != 30 || result.Attendance.SeatNumber != 5 {
+		t.Errorf("expected the earlier join to be kept, got %+v", result.Attendance)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestJoinClass_GuestAssignedSeat(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "total_capacity", "is_active"}).AddRow("class-1", "PUB1", "Test Class", 10, true))
+	expectNoStudentNamed(mock, "class-1", "guest")
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(7, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE "class_sessions"."id" = \$1 .* FOR UPDATE`).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND \(student_id IS NULL AND name_key = \$2\)`).
+		WithArgs(7, "guest", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM "join_waitlist" WHERE session_id = \$1 ORDER BY id`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	expectNoSeatingLayout(mock, "class-1")
+	mock.ExpectQuery(`SELECT "seat_number" FROM "attendance_records" WHERE session_id = \$1 AND seat_number > 0`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"seat_number"}).AddRow(1))
+	mock.ExpectQuery(`SELECT "preferred_seat_number" FROM "student_preferred_seats" WHERE class_id = \$1 AND \(NOT EXISTS`).
+		WithArgs("class-1", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"preferred_seat_number"}).AddRow(2))
+	mock.ExpectQuery(`INSERT INTO "attendance_records" .* ON CONFLICT DO NOTHING`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+	mock.ExpectCommit()
+
+	result, err := service.JoinClass(db, "PUB1", "Guest", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Seat 1 is taken and seat 2 is kept for an enrolled student who has not joined yet
+	if result.Attendance == nil || result.Attendance.SeatNumber != 3 {
+		t.Errorf("expected the guest to be assigned seat 3, got %+v", result.Attendance)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestJoinClass_Inactive(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "is_active"}).AddRow("class-1", "PUB1", "Test Class", false))
+	mock.ExpectRollback()
+
+	if _, err := service.JoinClass(db, "PUB1", "Guest", nil); !errors.Is(err, service.ErrClassInactive) {
+		t.Errorf("expected ErrClassInactive, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestJoinClass_Locked(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "is_active"}).AddRow("class-1", "PUB1", "Test Class", true))
+	expectNoStudentNamed(mock, "class-1", "guest")
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(7, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE "class_sessions"."id" = \$1 .* FOR UPDATE`).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "joins_locked"}).AddRow(7, true))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\)`).
+		WithArgs(7, "guest", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	if _, err := service.JoinClass(db, "PUB1", "Guest", nil); !errors.Is(err, service.ErrClassLocked) {
+		t.Errorf("expected ErrClassLocked, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+// expectFullSessionJoin expects a guest join to the open session 7 of an active class-1 with the given
+// capacity and wait-list setting, up to the capacity check, with two attendees and one joiner waiting.
+func expectFullSessionJoin(mock sqlmock.Sqlmock, capacity int, waitlist bool) {
+	mock.ExpectBegin()
+	expectFullSession(mock, capacity, waitlist)
+}
+
+// expectFullSession expects the queries of expectFullSessionJoin inside a transaction already begun.
+func expectFullSession(mock sqlmock.Sqlmock, capacity int, waitlist bool) {
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE public_id = \$1`).
+		WithArgs("PUB1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "total_capacity", "is_active", "waitlist_when_full"}).
+			AddRow("class-1", "PUB1", "Test Class", capacity, true, waitlist))
+	expectNoStudentNamed(mock, "class-1", "guest")
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(7, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE "class_sessions"."id" = \$1 .* FOR UPDATE`).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\)`).
+		WithArgs(7, "guest", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT \* FROM "join_waitlist" WHERE session_id = \$1 ORDER BY id`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "name"}).AddRow(1, 7, "Early Guest"))
+}
+
+func TestJoinClass_Full(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+	// One seat is left, but the joiner already waiting is first in line for it
+	expectFullSessionJoin(mock, 3, false)
+	mock.ExpectCommit()
+
+	if _, err := service.JoinClass(db, "PUB1", "Guest", nil); !errors.Is(err, service.ErrClassFull) {
+		t.Errorf("expected ErrClassFull, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestJoinClass_Waitlisted(t *testing.T) {
+	config.Init()
+	db, mock := setupMockDB(t)
+	expectFullSessionJoin(mock, 2, true)
+	mock.ExpectQuery(`INSERT INTO "join_waitlist"`).
+		WithArgs(7, "class-1", nil, "Guest", "guest", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	_, err := service.JoinClass(db, "PUB1", "Guest", nil)
+	var waitlisted *service.WaitlistedError
+	if !errors.As(err, &waitlisted) || waitlisted.Position != 2 || waitlisted.SessionID != 7 {
+		t.Errorf("expected to be second on the waiting list, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+// maxLayoutDimension is the largest number of rows or columns in a seating layout.
+const maxLayoutDimension = 50
+
+var (
+	// ErrInvalidLayout is wrapped by errors describing an invalid seating layout.
+	ErrInvalidLayout = errors.New("invalid seating layout")
+	// ErrLayoutExcludesEnrolledSeat is returned when a new layout drops a seat an enrolled student prefers.
+	ErrLayoutExcludesEnrolledSeat = errors.New("layout removes a seat assigned to an enrolled student")
+)
+
+// DefaultSeatingLayout returns the layout of a class without a saved one: a grid DefaultLayoutColumns wide
+// with a seat for each unit of capacity, so seats run from 1 to the class capacity.
+func DefaultSeatingLayout(class *model.Class) *model.SeatingLayout {
+	capacity := max(class.TotalCapacity, 1)
+	rows := (capacity + model.DefaultLayoutColumns - 1) / model.DefaultLayoutColumns
+	layout := &model.SeatingLayout{
+		ClassID:       class.ID,
+		Rows:          rows,
+		Columns:       model.DefaultLayoutColumns,
+		DisabledSeats: []int{},
+		Zones:         []model.SeatZone{},
+		SeatPolicy:    model.SeatPolicyNextFree,
+	}
+	for seat := capacity + 1; seat <= rows*model.DefaultLayoutColumns; seat++ {
+		layout.DisabledSeats = append(layout.DisabledSeats, seat)
+	}
+	return layout
+}
+
+// GetSeatingLayout fetches a class's seating layout, or its default layout if none has been saved.
+func GetSeatingLayout(db *gorm.DB, class *model.Class) (*model.SeatingLayout, error) {
+	var layout model.SeatingLayout
+	if err := db.Where("class_id = ?", class.ID).First(&layout).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return DefaultSeatingLayout(class), nil
+		}
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// SaveSeatingLayout replaces a class's seating layout. Every seat preferred by an enrolled student
+// must remain a seat of the new layout.
+func SaveSeatingLayout(db *gorm.DB, class *model.Class, req model.SeatingLayoutRequest) (*model.SeatingLayout, error) {
+	layout := &model.SeatingLayout{
+		ClassID:       class.ID,
+		Rows:          req.Rows,
+		Columns:       req.Columns,
+		DisabledSeats: req.DisabledSeats,
+		Zones:         req.Zones,
+		SeatPolicy:    req.SeatPolicy,
+	}
+	if layout.SeatPolicy == "" {
+		layout.SeatPolicy = model.SeatPolicyNextFree
+	}
+	if layout.DisabledSeats == nil {
+		layout.DisabledSeats = []int{}
+	}
+	if layout.Zones == nil {
+		layout.Zones = []model.SeatZone{}
+	}
+	for i := range layout.Zones {
+		layout.Zones[i].Name = strings.TrimSpace(layout.Zones[i].Name)
+	}
+	if err := validateLayout(layout); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLayout, err)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := checkEnrolledSeats(tx, class.ID, layout); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "class_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rows", "columns", "disabled_seats", "zones", "seat_policy", "updated_at"}),
+		}).Create(layout).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// checkEnrolledSeats returns ErrLayoutExcludesEnrolledSeat if a seat preferred by a student
+// enrolled in the class is not a seat of layout.
+func checkEnrolledSeats(db *gorm.DB, classID string, layout *model.SeatingLayout) error {
+	var seats []int
+	if err := db.Model(&model.StudentPreferredSeat{}).
+		Where("class_id = ?", classID).
+		Order("preferred_seat_number").
+		Pluck("preferred_seat_number", &seats).Error; err != nil {
+		return err
+	}
+	for _, seat := range seats {
+		if !layout.HasSeat(seat) {
+			return fmt.Errorf("%w: seat %d", ErrLayoutExcludesEnrolledSeat, seat)
+		}
+	}
+	return nil
+}
+
+// validateLayout checks a layout's dimensions, disabled cells, zones and seat policy.
+func validateLayout(layout *model.SeatingLayout) error {
+	if layout.Rows < 1 || layout.Rows > maxLayoutDimension || layout.Columns < 1 || layout.Columns > maxLayoutDimension {
+		return fmt.Errorf("rows and columns must be between 1 and %d", maxLayoutDimension)
+	}
+	cells := layout.Rows * layout.Columns
+	disabled := make(map[int]bool)
+	for _, seat := range layout.DisabledSeats {
+		if seat < 1 || seat > cells {
+			return fmt.Errorf("disabled seat %d is outside the %dx%d grid", seat, layout.Rows, layout.Columns)
+		}
+		disabled[seat] = true
+	}
+	if len(disabled) == cells {
+		return errors.New("the layout must have at least one seat")
+	}
+	switch layout.SeatPolicy {
+	case model.SeatPolicyNextFree, model.SeatPolicyFrontFirst, model.SeatPolicyFillByZone:
+	default:
+		return fmt.Errorf("unknown seat policy %q", layout.SeatPolicy)
+	}
+
+	zoneNames := make(map[string]bool)
+	zoneOf := make(map[int]string)
+	for _, zone := range layout.Zones {
+		name := zone.Name
+		if name == "" {
+			return errors.New("zones must have a name")
+		}
+		if zoneNames[strings.ToLower(name)] {
+			return fmt.Errorf("zone %q is defined more than once", name)
+		}
+		zoneNames[strings.ToLower(name)] = true
+		if len(zone.Seats) == 0 {
+			return fmt.Errorf("zone %q has no seats", name)
+		}
+		for _, seat := range zone.Seats {
+			if !layout.HasSeat(seat) {
+				return fmt.Errorf("zone %q includes seat %d, which is not a seat of the layout", name, seat)
+			}
+			if other, ok := zoneOf[seat]; ok {
+				return fmt.Errorf("seat %d is in both zone %q and zone %q", seat, other, name)
+			}
+			zoneOf[seat] = name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+// expectNoSeatingLayout expects a class's seating layout to be looked up and not found.
+func expectNoSeatingLayout(mock sqlmock.Sqlmock, classID string) {
+	mock.ExpectQuery(`SELECT \* FROM "seating_layouts" WHERE class_id = \$1`).
+		WithArgs(classID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"class_id"}))
+}
+
+func TestDefaultSeatingLayout(t *testing.T) {
+	layout := service.DefaultSeatingLayout(&model.Class{ID: "class-1", TotalCapacity: 12})
+
+	if layout.Rows != 3 || layout.Columns != model.DefaultLayoutColumns {
+		t.Errorf("expected a 3x5 grid, got %dx%d", layout.Rows, layout.Columns)
+	}
+	if seats := layout.Seats(); len(seats) != 12 || seats[11] != 12 {
+		t.Errorf("expected seats 1 to 12, got %v", seats)
+	}
+	if layout.HasSeat(13) {
+		t.Error("expected seat 13 to be disabled")
+	}
+}
+
+func TestGetSeatingLayout_Saved(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30}
+
+	mock.ExpectQuery(`SELECT \* FROM "seating_layouts" WHERE class_id = \$1`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"class_id", "rows", "columns", "disabled_seats", "zones"}).
+			AddRow("class-1", 2, 4, []byte(`[2,3]`), []byte(`[{"name":"Lab bench","seats":[5,6]}]`)))
+
+	layout, err := service.GetSeatingLayout(db, class)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if layout.HasSeat(2) || !layout.HasSeat(8) || layout.HasSeat(9) {
+		t.Errorf("expected seats 1 and 4 to 8 in a 2x4 grid, got %v", layout.Seats())
+	}
+	if len(layout.Zones) != 1 || layout.Zones[0].Name != "Lab bench" {
+		t.Errorf("expected the lab bench zone, got %+v", layout.Zones)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSaveSeatingLayout_Invalid(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30}
+
+	requests := []model.SeatingLayoutRequest{
+		{Rows: 0, Columns: 5},
+		{Rows: 2, Columns: 2, DisabledSeats: []int{5}},
+		{Rows: 1, Columns: 2, DisabledSeats: []int{1, 2}},
+		{Rows: 2, Columns: 2, Zones: []model.SeatZone{{Name: " ", Seats: []int{1}}}},
+		{Rows: 2, Columns: 2, DisabledSeats: []int{1}, Zones: []model.SeatZone{{Name: "Front", Seats: []int{1}}}},
+		{Rows: 2, Columns: 2, Zones: []model.SeatZone{{Name: "A", Seats: []int{1}}, {Name: "B", Seats: []int{1}}}},
+		{Rows: 2, Columns: 2, SeatPolicy: "back_first"},
+	}
+	for _, req := range requests {
+		if _, err := service.SaveSeatingLayout(db, class, req); !errors.Is(err, service.ErrInvalidLayout) {
+			t.Errorf("%+v: expected ErrInvalidLayout, got %v", req, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSaveSeatingLayout_ExcludesEnrolledSeat(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "preferred_seat_number" FROM "student_preferred_seats" WHERE class_id = \$1`).
+		WithArgs("class-1").
+		WillReturnRows(sqlmock.NewRows([]string{"preferred_seat_number"}).AddRow(1).AddRow(4))
+	mock.ExpectRollback()
+
+	_, err := service.SaveSeatingLayout(db, class, model.SeatingLayoutRequest{Rows: 2, Columns: 2, DisabledSeats: []int{4}})
+	if !errors.Is(err, service.ErrLayoutExcludesEnrolledSeat) {
+		t.Errorf("expected ErrLayoutExcludesEnrolledSeat, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSaveSeatingLayout(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "preferred_seat_number" FROM "student_preferred_seats"`).
+		WillReturnRows(sqlmock.NewRows([]string{"preferred_seat_number"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO "seating_layouts" .* ON CONFLICT \("class_id"\) DO UPDATE SET "rows"="excluded"."rows"`).
+		WithArgs("class-1", 2, 3, `[6]`, `[{"name":"U-shape","seats":[1,2,3]}]`, model.SeatPolicyNextFree, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	layout, err := service.SaveSeatingLayout(db, class, model.SeatingLayoutRequest{
+		Rows:          2,
+		Columns:       3,
+		DisabledSeats: []int{6},
+		Zones:         []model.SeatZone{{Name: " U-shape ", Seats: []int{1, 2, 3}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(layout.Seats()) != 5 || layout.Zones[0].Name != "U-shape" {
+		t.Errorf("unexpected layout: %+v", layout)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+// RemoveStudent takes a student, or the guest in cmd.SeatNumber, out of the class's current session.
+// Returns ErrNotInSession if they have not joined it.
+func RemoveStudent(db *gorm.DB, class *model.Class, cmd model.RemoveStudentCommand) (*model.AttendanceRecord, error) {
+	return leaveSession(db, class, model.LeaveReasonRemoved, func(tx *gorm.DB, session *model.ClassSession) (*model.AttendanceRecord, error) {
+		var record model.AttendanceRecord
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("session_id = ? AND left_at IS NULL", session.ID)
+		if cmd.StudentID != nil {
+			query = query.Where("student_id = ?", *cmd.StudentID)
+		} else {
+			query = query.Where("student_id IS NULL AND seat_number = ?", *cmd.SeatNumber)
+		}
+		if err := query.First(&record).Error; err != nil {
+			return nil, err
+		}
+		return &record, nil
+	})
+}
+
+// LeaveClass takes the student or guest a session token was issued to out of the class's current session.
+// Returns ErrNotInSession if the token is not for the current session, and ErrInvalidToken if
+// it was issued for an earlier join of the student that has already ended.
+func LeaveClass(db *gorm.DB, class *model.Class, claims *model.StudentSessionClaims) (*model.AttendanceRecord, error) {
+	if claims.ClassID != class.PublicID || claims.SessionID == nil {
+		return nil, ErrNotInSession
+	}
+	return leaveSession(db, class, model.LeaveReasonLeft, func(tx *gorm.DB, session *model.ClassSession) (*model.AttendanceRecord, error) {
+		if session.ID != *claims.SessionID {
+			return nil, gorm.ErrRecordNotFound
+		}
+		if claims.AttendanceID == nil {
+			return nil, ErrInvalidToken
+		}
+		var record model.AttendanceRecord
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND session_id = ?", *claims.AttendanceID, session.ID).
+			First(&record).Error; err != nil {
+			return nil, err
+		}
+		if record.LeftAt != nil {
+			return nil, ErrInvalidToken
+		}
+		return &record, nil
+	})
+}
+
+// leaveSession marks the attendance record find locks in the current session of a class as left for the
+// reason, and notifies the class dashboards with a student_left event giving it. The record and the
+// ledger are kept, but leaving frees the seat and ends the session tokens issued for the join; joining
+// again adds a new record. A guest's ledger entries are tied to their record, since guest entries are
+// keyed by seat and would otherwise pass to the next guest seated there.
+// When joiners are waiting for a seat, a seat_available event names the next one to be admitted.
+func leaveSession(
+	db *gorm.DB,
+	class *model.Class,
+	reason string,
+	find func(tx *gorm.DB, session *model.ClassSession) (*model.AttendanceRecord, error),
+) (*model.AttendanceRecord, error) {
+	var record *model.AttendanceRecord
+	var waitlist []model.WaitlistEntry
+	err := db.Transaction(func(tx *gorm.DB) error {
+		session, err := GetCurrentSession(tx, class.ID)
+		if err != nil {
+			return err
+		}
+		record, err = find(tx, session)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotInSession
+			}
+			return err
+		}
+
+		now := time.Now()
+		record.LeftAt = &now
+		record.LeftReason = reason
+		if err := tx.Model(record).Select("left_at", "left_reason").Updates(record).Error; err != nil {
+			return err
+		}
+		if record.StudentID == nil && record.SeatNumber > 0 {
+			if err := tx.Model(&model.PointEvent{}).
+				Where("session_id = ? AND student_id IS NULL AND seat_number = ? AND attendance_id IS NULL", session.ID, record.SeatNumber).
+				Update("attendance_id", record.ID).Error; err != nil {
+				return err
+			}
+		}
+		waitlist, err = ListWaitlist(tx, session.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	BroadcastEvent(class.PublicID, model.StudentLeftEvent{
+		StudentID:  record.StudentID,
+		Name:       record.Name,
+		SeatNumber: record.SeatNumber,
+		Reason:     reason,
+	})
+	if len(waitlist) > 0 {
+		BroadcastEvent(class.PublicID, model.SeatAvailableEvent{
+			SessionID:      record.SessionID,
+			SeatNumber:     record.SeatNumber,
+			Next:           waitlist[0],
+			WaitlistLength: len(waitlist),
+		})
+	}
+	return record, nil
+}
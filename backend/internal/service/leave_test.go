@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestRemoveStudent_GuestKeepsHistory(t *testing.T) {
+	db, mock := setupMockDB(t)
+	seat := 4
+	class := &model.Class{ID: "class-1", PublicID: "PUB1"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND \(student_id IS NULL AND seat_number = \$2\) .* FOR UPDATE`).
+		WithArgs(uint(3), seat, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number"}).AddRow(9, 3, nil, "Guest", 4))
+	// The record is marked as left, and the guest's points are tied to it rather than to the seat
+	mock.ExpectExec(`UPDATE "attendance_records" SET "left_at"=\$1,"left_reason"=\$2,"updated_at"=\$3 WHERE "id" = \$4`).
+		WithArgs(sqlmock.AnyArg(), model.LeaveReasonRemoved, sqlmock.AnyArg(), uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "point_events" SET "attendance_id"=\$1 WHERE session_id = \$2 AND student_id IS NULL AND seat_number = \$3 AND attendance_id IS NULL`).
+		WithArgs(uint(9), uint(3), 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`SELECT \* FROM "join_waitlist" WHERE session_id = \$1 ORDER BY id`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	record, err := service.RemoveStudent(db, class, model.RemoveStudentCommand{SeatNumber: &seat})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.ID != 9 || record.Name != "Guest" || record.LeftAt == nil || record.LeftReason != model.LeaveReasonRemoved {
+		t.Errorf("unexpected record removed: %+v", record)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRemoveStudent_SeatAvailable(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(5)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1"}
+	_, conn := connectClient(t, "PUB1", 1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND student_id = \$2 .* FOR UPDATE`).
+		WithArgs(uint(3), studentID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number"}).AddRow(9, 3, 5, "Alice", 6))
+	mock.ExpectExec(`UPDATE "attendance_records" SET "left_at"=\$1,"left_reason"=\$2,"updated_at"=\$3 WHERE "id" = \$4`).
+		WithArgs(sqlmock.AnyArg(), model.LeaveReasonRemoved, sqlmock.AnyArg(), uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "join_waitlist" WHERE session_id = \$1 ORDER BY id`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "class_id", "name"}).
+			AddRow(2, 3, "class-1", "Bob").
+			AddRow(4, 3, "class-1", "Carol"))
+	mock.ExpectCommit()
+
+	if _, err := service.RemoveStudent(db, class, model.RemoveStudentCommand{StudentID: &studentID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The teacher is told who is next on the waiting list
+	data := conn.waitForMessage(t, model.EventSeatAvailable).Data.(map[string]interface{})
+	next := data["next"].(map[string]interface{})
+	if data["seatNumber"] != float64(6) || data["waitlistLength"] != float64(2) || next["name"] != "Bob" {
+		t.Errorf("unexpected seat_available event: %v", data)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRemoveStudent_NotInSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(5)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND student_id = \$2 .* FOR UPDATE`).
+		WithArgs(uint(3), studentID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err := service.RemoveStudent(db, class, model.RemoveStudentCommand{StudentID: &studentID})
+	if !errors.Is(err, service.ErrNotInSession) {
+		t.Errorf("expected ErrNotInSession, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestLeaveClass_EarlierJoin(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(5)
+	sessionID := uint(3)
+	attendanceID := uint(8)
+	leftAt := time.Now()
+	class := &model.Class{ID: "class-1", PublicID: "PUB1"}
+	// The token is from before the student left and joined again, even within the same second
+	claims := &model.StudentSessionClaims{
+		StudentID:    &studentID,
+		Name:         "Philip",
+		ClassID:      "PUB1",
+		SessionID:    &sessionID,
+		AttendanceID: &attendanceID,
+		IssuedAt:     leftAt.Unix(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE id = \$1 AND session_id = \$2 .* FOR UPDATE`).
+		WithArgs(attendanceID, uint(3), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number", "left_at"}).
+			AddRow(8, 3, studentID, "Philip", 4, leftAt))
+	mock.ExpectRollback()
+
+	_, err := service.LeaveClass(db, class, claims)
+	if !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestLeaveClass_OtherClass(t *testing.T) {
+	db, mock := setupMockDB(t)
+	sessionID := uint(3)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1"}
+
+	_, err := service.LeaveClass(db, class, &model.StudentSessionClaims{Name: "Guest", ClassID: "PUB2", SessionID: &sessionID})
+	if !errors.Is(err, service.ErrNotInSession) {
+		t.Errorf("expected ErrNotInSession, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
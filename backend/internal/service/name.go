@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+)
+
+// ErrAmbiguousName is returned when a name matches several students and nothing tells them apart.
+var ErrAmbiguousName = errors.New("several students have this name")
+
+// AmbiguousNameError is returned when a name typed at join matches several students of a class.
+// The joiner picks one of Choices and joins again with its student ID.
+type AmbiguousNameError struct {
+	Name    string
+	Choices []model.NameChoice
+}
+
+func (e *AmbiguousNameError) Error() string {
+	return fmt.Sprintf("%v: %d students match %q", ErrAmbiguousName, len(e.Choices), e.Name)
+}
+
+// Unwrap lets errors.Is match ErrAmbiguousName.
+func (e *AmbiguousNameError) Unwrap() error { return ErrAmbiguousName }
+
+// CleanStudentName tidies a typed name for display and storage: Unicode composed form,
+// no leading or trailing spaces and single spaces between words.
+func CleanStudentName(name string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+}
+
+// studentNameKey is the form names are matched on: the cleaned name, compatibility-normalized
+// so look-alike characters (such as full-width letters) compare equal, and case-folded.
+func studentNameKey(name string) string {
+	return cases.Fold().String(norm.NFKC.String(CleanStudentName(name)))
+}
+
+// studentNameMatch is a student whose name matches a typed name, with their preferred seat
+// in the class being joined (0 when they are not enrolled in it).
+type studentNameMatch struct {
+	model.Student `gorm:"embedded"`
+	SeatNumber    int
+}
+
+// resolveStudentName finds the student a name typed at join refers to in a class. Names are compared
+// by studentNameKey, so "james " finds James. Only students enrolled in the class are considered, and only
+// whole names match: neither a student of another class nor a first name alone ever finds a student,
+// so a guest cannot join as someone else. Returns nil for a guest, or an *AmbiguousNameError when more
+// than one student remains and studentID, the student picked from an earlier error's choices, is not one of them.
+func resolveStudentName(db *gorm.DB, class *model.Class, name string, studentID *uint) (*model.Student, error) {
+	key := studentNameKey(name)
+	var matches []studentNameMatch
+	if err := db.Table("students AS s").
+		Select("s.*, COALESCE(sps.preferred_seat_number, 0) AS seat_number").
+		Joins("LEFT JOIN student_preferred_seats sps ON sps.student_id = s.id AND sps.class_id = ?", class.ID).
+		Where("s.name_key = ?", key).
+		Order("s.id").
+		Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	// Only the class's own students can be told apart by the joiner; someone matching
+	// students of other classes joins as a guest rather than as one of them
+	matches = enrolledMatches(matches)
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0].Student, nil
+	}
+	if studentID != nil {
+		for _, match := range matches {
+			if match.ID == *studentID {
+				return &match.Student, nil
+			}
+		}
+	}
+	choices := make([]model.NameChoice, 0, len(matches))
+	for _, match := range matches {
+		choices = append(choices, model.NameChoice{
+			StudentID:  match.ID,
+			Label:      nameChoiceLabel(match.Name, match.SeatNumber),
+			SeatNumber: match.SeatNumber,
+		})
+	}
+	return nil, &AmbiguousNameError{Name: name, Choices: choices}
+}
+
+// enrolledMatches keeps the matches enrolled in the class.
+func enrolledMatches(matches []studentNameMatch) []studentNameMatch {
+	var enrolled []studentNameMatch
+	for _, match := range matches {
+		if match.SeatNumber > 0 {
+			enrolled = append(enrolled, match)
+		}
+	}
+	return enrolled
+}
+
+// nameChoiceLabel describes a student to a joiner picking between students with the same name:
+// their first name with the initial of their last name, and their seat if they have one.
+// Full names stay off the join page, which anyone holding the QR code can open.
+func nameChoiceLabel(name string, seatNumber int) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+	label := words[0]
+	if len(words) > 1 {
+		last := []rune(words[len(words)-1])
+		label += " " + string(unicode.ToUpper(last[0])) + "."
+	}
+	if seatNumber > 0 {
+		label += fmt.Sprintf(" (seat %d)", seatNumber)
+	}
+	return label
+}
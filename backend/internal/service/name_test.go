@@ -0,0 +1,44 @@
+package service
+
+import "testing"
+
+func TestStudentNameKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "spacing", a: "  James   Smith ", b: "James Smith"},
+		{name: "case", a: "JAMES", b: "james"},
+		{name: "full-width letters", a: "Ｊａｍｅｓ", b: "James"},
+		{name: "combining accent", a: "Jose\u0301", b: "Jos\u00e9"},
+		{name: "case folding", a: "STRASSE", b: "straße"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if studentNameKey(tt.a) != studentNameKey(tt.b) {
+				t.Errorf("expected %q and %q to match, got keys %q and %q", tt.a, tt.b, studentNameKey(tt.a), studentNameKey(tt.b))
+			}
+		})
+	}
+
+	if got := CleanStudentName("  José   Smith "); got != "José Smith" {
+		t.Errorf("expected cleaned name %q, got %q", "José Smith", got)
+	}
+}
+
+func TestNameChoiceLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		seatNumber int
+		want       string
+	}{
+		{name: "James Smith", seatNumber: 4, want: "James S. (seat 4)"},
+		{name: "James van der berg", want: "James B."},
+		{name: "James", seatNumber: 12, want: "James (seat 12)"},
+	}
+	for _, tt := range tests {
+		if got := nameChoiceLabel(tt.name, tt.seatNumber); got != tt.want {
+			t.Errorf("nameChoiceLabel(%q, %d) = %q, want %q", tt.name, tt.seatNumber, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+)
+
+var (
+	// ErrInvalidPairingConstraint is wrapped by errors describing an invalid pairing constraint request.
+	ErrInvalidPairingConstraint = errors.New("invalid pairing constraint")
+	// ErrPairingConstraintExists is returned when a pair of students already has a constraint in the class.
+	ErrPairingConstraintExists = errors.New("these students already have a pairing constraint in this class")
+)
+
+// PairingConstraintsError is returned when groups of the requested size cannot honor every pairing constraint.
+type PairingConstraintsError struct {
+	Unsatisfied []model.UnsatisfiedConstraint
+}
+
+func (e *PairingConstraintsError) Error() string {
+	reasons := make([]string, 0, len(e.Unsatisfied))
+	for _, unsatisfied := range e.Unsatisfied {
+		reasons = append(reasons, unsatisfied.Reason)
+	}
+	return "pairing constraints cannot be satisfied: " + strings.Join(reasons, "; ")
+}
+
+// maxPlacementSteps bounds the search for groups that honor every keep-apart rule,
+// so a class with many conflicting rules fails fast instead of stalling a request.
+const maxPlacementSteps = 100000
+
+// ListPairingConstraints fetches a class's pairing constraints in the order they were added.
+func ListPairingConstraints(db *gorm.DB, classID string) ([]model.PairingConstraint, error) {
+	constraints := []model.PairingConstraint{}
+	result := db.Where("class_id = ?", classID).Order("id").Find(&constraints)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return constraints, nil
+}
+
+// AddPairingConstraint adds a keep-apart or keep-together rule between two students enrolled in a class.
+func AddPairingConstraint(db *gorm.DB, classID string, req model.PairingConstraintRequest) (*model.PairingConstraint, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPairingConstraint, err)
+	}
+	a, b := req.StudentIDs[0], req.StudentIDs[1]
+	if a > b {
+		a, b = b, a
+	}
+
+	var enrolled int64
+	if err := db.Model(&model.StudentPreferredSeat{}).
+		Where("class_id = ? AND student_id IN ?", classID, []uint{a, b}).
+		Count(&enrolled).Error; err != nil {
+		return nil, err
+	}
+	if enrolled < 2 {
+		return nil, ErrNotEnrolled
+	}
+
+	constraint := &model.PairingConstraint{ClassID: classID, Kind: req.Kind, StudentAID: a, StudentBID: b}
+	if err := db.Create(constraint).Error; err != nil {
+		if constraintViolation(err) == "unique_pairing_constraint_pair" {
+			return nil, ErrPairingConstraintExists
+		}
+		return nil, err
+	}
+	return constraint, nil
+}
+
+// DeletePairingConstraint removes a pairing constraint from a class.
+func DeletePairingConstraint(db *gorm.DB, classID string, constraintID uint) error {
+	result := db.Where("class_id = ?", classID).Delete(&model.PairingConstraint{}, constraintID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// pairRule is a pairing constraint between two candidates, identified by their index.
+type pairRule struct {
+	constraint model.PairingConstraint
+	a, b       int
+}
+
+// groupPlacement searches for an assignment of units, sets of candidates kept together, to groups
+// that fills every group to its size without putting two units that must be kept apart together.
+type groupPlacement struct {
+	sizes     []int
+	capacity  []int
+	unitSizes []int
+	// preferred is the group the strategy put most of each unit's members in
+	preferred []int
+	conflicts [][]int
+	groupOf   []int
+	// order lists units from hardest to easiest to place
+	order []int
+	steps int
+}
+
+// place places the units from position i of the order onwards, backtracking on dead ends.
+func (p *groupPlacement) place(i int) bool {
+	if i == len(p.order) {
+		return true
+	}
+	p.steps++
+	if p.steps > maxPlacementSteps {
+		return false
+	}
+	u := p.order[i]
+	for _, g := range p.choices(u) {
+		if p.capacity[g] < p.unitSizes[u] || p.conflictsIn(u, g) > 0 {
+			continue
+		}
+		p.groupOf[u] = g
+		p.capacity[g] -= p.unitSizes[u]
+		if p.place(i + 1) {
+			return true
+		}
+		p.groupOf[u] = -1
+		p.capacity[g] += p.unitSizes[u]
+	}
+	return false
+}
+
+// placeGreedily places every unit that fits somewhere, in the group it conflicts with least,
+// so the rules that cannot be honored can be reported. It returns the units that fit nowhere.
+func (p *groupPlacement) placeGreedily() []int {
+	copy(p.capacity, p.sizes)
+	for u := range p.groupOf {
+		p.groupOf[u] = -1
+	}
+
+	var unplaced []int
+	for _, u := range p.order {
+		best := -1
+		for _, g := range p.choices(u) {
+			if p.capacity[g] >= p.unitSizes[u] && (best == -1 || p.conflictsIn(u, g) < p.conflictsIn(u, best)) {
+				best = g
+			}
+		}
+		if best == -1 {
+			unplaced = append(unplaced, u)
+			continue
+		}
+		p.groupOf[u] = best
+		p.capacity[best] -= p.unitSizes[u]
+	}
+	return unplaced
+}
+
+// choices lists the groups to try for a unit: the one the strategy chose, then those with the most room left.
+func (p *groupPlacement) choices(u int) []int {
+	groups := make([]int, len(p.capacity))
+	for g := range groups {
+		groups[g] = g
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		gi, gj := groups[i], groups[j]
+		if (gi == p.preferred[u]) != (gj == p.preferred[u]) {
+			return gi == p.preferred[u]
+		}
+		return p.capacity[gi] > p.capacity[gj]
+	})
+	return groups
+}
+
+// conflictsIn counts the units already in group g that unit u must be kept apart from.
+func (p *groupPlacement) conflictsIn(u, g int) int {
+	count := 0
+	for _, other := range p.conflicts[u] {
+		if p.groupOf[other] == g {
+			count++
+		}
+	}
+	return count
+}
+
+// applyPairingConstraints rearranges a strategy's groups, keeping their sizes, so that every pairing constraint
+// between candidates is honored. Candidates stay in the group the strategy chose wherever the rules allow.
+// Constraints naming a student who is not being grouped are ignored. If the rules cannot all be honored,
+// the ones that could not are returned instead.
+func applyPairingConstraints(groups [][]GroupCandidate, constraints []model.PairingConstraint) ([][]GroupCandidate, []model.UnsatisfiedConstraint) {
+	var candidates []GroupCandidate
+	var strategyGroup []int
+	index := make(map[uint]int)
+	sizes := make([]int, len(groups))
+	for g, group := range groups {
+		sizes[g] = len(group)
+		for _, candidate := range group {
+			if candidate.Member.StudentID != nil {
+				index[*candidate.Member.StudentID] = len(candidates)
+			}
+			candidates = append(candidates, candidate)
+			strategyGroup = append(strategyGroup, g)
+		}
+	}
+
+	var apart, together []pairRule
+	for _, constraint := range constraints {
+		a, okA := index[constraint.StudentAID]
+		b, okB := index[constraint.StudentBID]
+		if !okA || !okB {
+			continue
+		}
+		rule := pairRule{constraint: constraint, a: a, b: b}
+		if constraint.Kind == model.PairingKeepTogether {
+			together = append(together, rule)
+		} else {
+			apart = append(apart, rule)
+		}
+	}
+	if len(apart) == 0 && len(together) == 0 {
+		return groups, nil
+	}
+
+	unsatisfied := func(rule pairRule, reason string) model.UnsatisfiedConstraint {
+		names := []string{candidates[rule.a].Member.Name, candidates[rule.b].Member.Name}
+		return model.UnsatisfiedConstraint{
+			Constraint: rule.constraint,
+			Students:   names,
+			Reason:     fmt.Sprintf(reason, names[0], names[1]),
+		}
+	}
+
+	// Candidates kept together, directly or through a chain of rules, are placed as one unit
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, rule := range together {
+		parent[find(rule.a)] = find(rule.b)
+	}
+	unitOf := make([]int, len(candidates))
+	unitIDs := make(map[int]int)
+	var units [][]int
+	for i := range candidates {
+		root := find(i)
+		id, ok := unitIDs[root]
+		if !ok {
+			id = len(units)
+			unitIDs[root] = id
+			units = append(units, nil)
+		}
+		unitOf[i] = id
+		units[id] = append(units[id], i)
+	}
+
+	largest := 0
+	for _, size := range sizes {
+		largest = max(largest, size)
+	}
+	var impossible []model.UnsatisfiedConstraint
+	for _, rule := range together {
+		if size := len(units[unitOf[rule.a]]); size > largest {
+			impossible = append(impossible, unsatisfied(rule, fmt.Sprintf(
+				"%%s and %%s must stay together, but keep-together rules join %d students and groups hold at most %d",
+				size, largest)))
+		}
+	}
+	for _, rule := range apart {
+		if unitOf[rule.a] == unitOf[rule.b] {
+			impossible = append(impossible, unsatisfied(rule,
+				"%s and %s must be kept apart, but keep-together rules put them in the same group"))
+		}
+	}
+	if len(impossible) > 0 {
+		return nil, impossible
+	}
+
+	p := &groupPlacement{
+		sizes:     sizes,
+		capacity:  append([]int(nil), sizes...),
+		unitSizes: make([]int, len(units)),
+		preferred: make([]int, len(units)),
+		conflicts: make([][]int, len(units)),
+		groupOf:   make([]int, len(units)),
+		order:     make([]int, len(units)),
+	}
+	for u, members := range units {
+		p.unitSizes[u] = len(members)
+		p.groupOf[u] = -1
+		p.order[u] = u
+		votes := make([]int, len(sizes))
+		for _, i := range members {
+			votes[strategyGroup[i]]++
+		}
+		for g := range votes {
+			if votes[g] > votes[p.preferred[u]] {
+				p.preferred[u] = g
+			}
+		}
+	}
+	for _, rule := range apart {
+		ua, ub := unitOf[rule.a], unitOf[rule.b]
+		p.conflicts[ua] = append(p.conflicts[ua], ub)
+		p.conflicts[ub] = append(p.conflicts[ub], ua)
+	}
+	sort.SliceStable(p.order, func(i, j int) bool {
+		ui, uj := p.order[i], p.order[j]
+		if p.unitSizes[ui] != p.unitSizes[uj] {
+			return p.unitSizes[ui] > p.unitSizes[uj]
+		}
+		return len(p.conflicts[ui]) > len(p.conflicts[uj])
+	})
+
+	if !p.place(0) {
+		unplaced := make(map[int]bool)
+		for _, u := range p.placeGreedily() {
+			unplaced[u] = true
+		}
+		var failed []model.UnsatisfiedConstraint
+		for _, rule := range together {
+			if unit := unitOf[rule.a]; unplaced[unit] {
+				failed = append(failed, unsatisfied(rule, fmt.Sprintf(
+					"%%s and %%s must stay together, but no group has room left for the %d students keep-together rules join",
+					p.unitSizes[unit])))
+			}
+		}
+		for _, rule := range apart {
+			ga, gb := p.groupOf[unitOf[rule.a]], p.groupOf[unitOf[rule.b]]
+			if ga != -1 && ga == gb {
+				failed = append(failed, unsatisfied(rule, fmt.Sprintf(
+					"%%s and %%s must be kept apart, but %d groups of this size cannot separate everyone with keep-apart rules",
+					len(sizes))))
+			}
+		}
+		// The search can run out of steps on an arrangement the greedy pass happens to find
+		if len(failed) > 0 || len(unplaced) > 0 {
+			return nil, failed
+		}
+	}
+
+	placed := make([][]GroupCandidate, len(groups))
+	for i, candidate := range candidates {
+		g := p.groupOf[unitOf[i]]
+		placed[g] = append(placed[g], candidate)
+	}
+	return placed, nil
+}
@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestAddPairingConstraint_Invalid(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	requests := []model.PairingConstraintRequest{
+		{Kind: "near", StudentIDs: []uint{1, 2}},
+		{Kind: model.PairingKeepApart, StudentIDs: []uint{1}},
+		{Kind: model.PairingKeepTogether, StudentIDs: []uint{3, 3}},
+	}
+	for _, req := range requests {
+		if _, err := service.AddPairingConstraint(db, "class-1", req); !errors.Is(err, service.ErrInvalidPairingConstraint) {
+			t.Errorf("%+v: expected ErrInvalidPairingConstraint, got %v", req, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAddPairingConstraint_NotEnrolled(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "student_preferred_seats" WHERE class_id = \$1 AND student_id IN \(\$2,\$3\)`).
+		WithArgs("class-1", uint(2), uint(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err := service.AddPairingConstraint(db, "class-1", model.PairingConstraintRequest{
+		Kind:       model.PairingKeepApart,
+		StudentIDs: []uint{7, 2},
+	})
+	if !errors.Is(err, service.ErrNotEnrolled) {
+		t.Errorf("expected ErrNotEnrolled, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAddPairingConstraint_Exists(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "student_preferred_seats"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "pairing_constraints" \("class_id","kind","student_a_id","student_b_id","created_at"\)`).
+		WithArgs("class-1", model.PairingKeepTogether, uint(2), uint(7), sqlmock.AnyArg()).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "unique_pairing_constraint_pair"})
+	mock.ExpectRollback()
+
+	_, err := service.AddPairingConstraint(db, "class-1", model.PairingConstraintRequest{
+		Kind:       model.PairingKeepTogether,
+		StudentIDs: []uint{7, 2},
+	})
+	if !errors.Is(err, service.ErrPairingConstraintExists) {
+		t.Errorf("expected ErrPairingConstraintExists, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDeletePairingConstraint_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "pairing_constraints" WHERE class_id = \$1 AND "pairing_constraints"."id" = \$2`).
+		WithArgs("class-1", uint(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := service.DeletePairingConstraint(db, "class-1", 4); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
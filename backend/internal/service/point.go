@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+var (
+	// ErrInvalidPointTarget is returned when a point event names neither a student nor a seat,
+	// or names a student or guest seat that is not part of the class session.
+	ErrInvalidPointTarget = errors.New("a student or seat in the class session is required")
+	// ErrInsufficientPoints is returned when a deduction would take a balance below zero.
+	ErrInsufficientPoints = errors.New("points cannot go below zero")
+)
+
+// pointTargetScope restricts a session's point_events query to a single student, or to the guest in a seat.
+// Entries of guests who have left the seat are tied to their attendance record and left out.
+func pointTargetScope(db *gorm.DB, sessionID uint, studentID *uint, seatNumber *int) *gorm.DB {
+	query := db.Model(&model.PointEvent{}).Where("session_id = ?", sessionID)
+	if studentID != nil {
+		return query.Where("student_id = ?", *studentID)
+	}
+	return query.Where("student_id IS NULL AND seat_number = ? AND attendance_id IS NULL", *seatNumber)
+}
+
+// GetPointTotal returns the current point balance of a student or guest seat in a class session.
+func GetPointTotal(db *gorm.DB, sessionID uint, studentID *uint, seatNumber *int) (int, error) {
+	if studentID == nil && (seatNumber == nil || *seatNumber <= 0) {
+		return 0, ErrInvalidPointTarget
+	}
+	var total int
+	result := pointTargetScope(db, sessionID, studentID, seatNumber).Select("COALESCE(SUM(delta), 0)").Scan(&total)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return total, nil
+}
+
+// pointTargetInSession reports whether a point event's target belongs to its session:
+// a student attending it or enrolled in the class, or a seat held by a guest who has not left.
+func pointTargetInSession(db *gorm.DB, event *model.PointEvent) (bool, error) {
+	var found int64
+	if event.StudentID == nil {
+		err := db.Model(&model.AttendanceRecord{}).
+			Where("session_id = ? AND student_id IS NULL AND seat_number = ? AND left_at IS NULL", event.SessionID, *event.SeatNumber).
+			Count(&found).Error
+		return found > 0, err
+	}
+
+	if err := db.Model(&model.AttendanceRecord{}).
+		Where("session_id = ? AND student_id = ? AND left_at IS NULL", event.SessionID, *event.StudentID).
+		Count(&found).Error; err != nil || found > 0 {
+		return found > 0, err
+	}
+	err := db.Model(&model.StudentPreferredSeat{}).
+		Where("class_id = ? AND student_id = ?", event.ClassID, *event.StudentID).
+		Count(&found).Error
+	return found > 0, err
+}
+
+// RecordPointEvent appends an event to its session's ledger and returns the resulting balance.
+// Targets outside the session are rejected with ErrInvalidPointTarget, and deductions that
+// would take the balance below zero with ErrInsufficientPoints.
+func RecordPointEvent(db *gorm.DB, event *model.PointEvent) (int, error) {
+	if event.StudentID == nil && (event.SeatNumber == nil || *event.SeatNumber <= 0) {
+		return 0, ErrInvalidPointTarget
+	}
+
+	var total int
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Lock the session row so concurrent ledger writes for the session are serialized
+		var session model.ClassSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND class_id = ?", event.SessionID, event.ClassID).First(&session).Error; err != nil {
+			return err
+		}
+
+		inSession, err := pointTargetInSession(tx, event)
+		if err != nil {
+			return err
+		}
+		if !inSession {
+			return ErrInvalidPointTarget
+		}
+
+		current, err := GetPointTotal(tx, event.SessionID, event.StudentID, event.SeatNumber)
+		if err != nil {
+			return err
+		}
+		if current+event.Delta < 0 {
+			return ErrInsufficientPoints
+		}
+
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		total = current + event.Delta
+		return nil
+	})
+
+	return total, err
+}
+
+// RecordSessionPoints records points for a student or guest seat in the class's current session,
+// multiplied by sign and attributed to the teacher, and notifies the class dashboards of the new balance.
+func RecordSessionPoints(db *gorm.DB, class *model.Class, req model.PointRequest, teacher string, sign int) (*model.PointEvent, int, error) {
+	session, err := GetCurrentSession(db, class.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	event := &model.PointEvent{
+		ClassID:    class.ID,
+		SessionID:  session.ID,
+		StudentID:  req.StudentID,
+		SeatNumber: req.SeatNumber,
+		Delta:      sign * req.Points,
+		Reason:     req.Reason,
+		Teacher:    teacher,
+	}
+	total, err := RecordPointEvent(db, event)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	BroadcastEvent(class.PublicID, model.PointsChangedEvent{
+		SessionID:  event.SessionID,
+		StudentID:  event.StudentID,
+		SeatNumber: event.SeatNumber,
+		Delta:      event.Delta,
+		Total:      total,
+		Reason:     event.Reason,
+	})
+	return event, total, nil
+}
+
+// GetPointTotals returns the balance of every student and guest seat with ledger entries in a class session.
+// Guest seats count only the entries of the guest seated there now.
+func GetPointTotals(db *gorm.DB, sessionID uint) ([]model.PointTotal, error) {
+	var totals []model.PointTotal
+	result := db.Model(&model.PointEvent{}).
+		Select("student_id, CASE WHEN student_id IS NULL THEN seat_number END AS seat_number, SUM(delta) AS total").
+		Where("session_id = ? AND (student_id IS NOT NULL OR attendance_id IS NULL)", sessionID).
+		Group("1, 2").
+		Order("1 NULLS LAST, 2").
+		Scan(&totals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return totals, nil
+}
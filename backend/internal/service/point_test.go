@@ -0,0 +1,195 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestRecordPointEvent_InvalidTarget(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	_, err := service.RecordPointEvent(db, &model.PointEvent{ClassID: "class-1", Delta: 1})
+	if !errors.Is(err, service.ErrInvalidPointTarget) {
+		t.Errorf("expected ErrInvalidPointTarget, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecordPointEvent_Award(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(7)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE id = \$1 AND class_id = \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs(uint(3), "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1 AND student_id = \$2 AND left_at IS NULL`).
+		WithArgs(uint(3), studentID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(delta\), 0\) FROM "point_events" WHERE session_id = \$1 AND student_id = \$2`).
+		WithArgs(uint(3), studentID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(3))
+	mock.ExpectQuery(`INSERT INTO "point_events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	event := &model.PointEvent{ClassID: "class-1", SessionID: 3, StudentID: &studentID, Delta: 2, Reason: "Great answer"}
+	total, err := service.RecordPointEvent(db, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("expected total 5, got %d", total)
+	}
+	if event.ID != 1 {
+		t.Errorf("expected event ID to be set, got %d", event.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecordSessionPoints_RecordsTeacher(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1"}
+	studentID := uint(7)
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE id = \$1 AND class_id = \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs(uint(3), "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1 AND student_id = \$2 AND left_at IS NULL`).
+		WithArgs(uint(3), studentID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "student_preferred_seats" WHERE class_id = \$1 AND student_id = \$2`).
+		WithArgs("class-1", studentID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(delta\), 0\) FROM "point_events" WHERE session_id = \$1 AND student_id = \$2`).
+		WithArgs(uint(3), studentID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO "point_events"`).
+		WithArgs("class-1", uint(3), studentID, nil, nil, 2, "Great answer", "teacher@example.com", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	req := model.PointRequest{StudentID: &studentID, Points: 2, Reason: "Great answer"}
+	event, _, err := service.RecordSessionPoints(db, class, req, "teacher@example.com", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Teacher != "teacher@example.com" {
+		t.Errorf("expected event to record the authenticated teacher, got %q", event.Teacher)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecordPointEvent_InsufficientPoints(t *testing.T) {
+	db, mock := setupMockDB(t)
+	seat := 4
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE id = \$1 AND class_id = \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs(uint(3), "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1 AND student_id IS NULL AND seat_number = \$2 AND left_at IS NULL`).
+		WithArgs(uint(3), seat).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(delta\), 0\) FROM "point_events" WHERE session_id = \$1 AND \(student_id IS NULL AND seat_number = \$2 AND attendance_id IS NULL\)`).
+		WithArgs(uint(3), seat).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(1))
+	mock.ExpectRollback()
+
+	_, err := service.RecordPointEvent(db, &model.PointEvent{ClassID: "class-1", SessionID: 3, SeatNumber: &seat, Delta: -2})
+	if !errors.Is(err, service.ErrInsufficientPoints) {
+		t.Errorf("expected ErrInsufficientPoints, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecordPointEvent_TargetNotInSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(9)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE id = \$1 AND class_id = \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs(uint(3), "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1 AND student_id = \$2 AND left_at IS NULL`).
+		WithArgs(uint(3), studentID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "student_preferred_seats" WHERE class_id = \$1 AND student_id = \$2`).
+		WithArgs("class-1", studentID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	_, err := service.RecordPointEvent(db, &model.PointEvent{ClassID: "class-1", SessionID: 3, StudentID: &studentID, Delta: 1})
+	if !errors.Is(err, service.ErrInvalidPointTarget) {
+		t.Errorf("expected ErrInvalidPointTarget, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecordPointEvent_VacatedGuestSeat(t *testing.T) {
+	db, mock := setupMockDB(t)
+	seat := 4
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE id = \$1 AND class_id = \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs(uint(3), "class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1 AND student_id IS NULL AND seat_number = \$2 AND left_at IS NULL`).
+		WithArgs(uint(3), seat).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	_, err := service.RecordPointEvent(db, &model.PointEvent{ClassID: "class-1", SessionID: 3, SeatNumber: &seat, Delta: 1})
+	if !errors.Is(err, service.ErrInvalidPointTarget) {
+		t.Errorf("expected ErrInvalidPointTarget, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetPointTotals(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT student_id, CASE WHEN student_id IS NULL THEN seat_number END AS seat_number, SUM\(delta\) AS total FROM "point_events" WHERE session_id = \$1 AND \(student_id IS NOT NULL OR attendance_id IS NULL\) GROUP BY 1, 2`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "seat_number", "total"}).
+			AddRow(1, nil, 5).
+			AddRow(nil, 12, 2))
+
+	totals, err := service.GetPointTotals(db, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(totals) != 2 {
+		t.Fatalf("expected 2 totals, got %d", len(totals))
+	}
+	if totals[0].StudentID == nil || *totals[0].StudentID != 1 || totals[0].Total != 5 {
+		t.Errorf("unexpected student total: %+v", totals[0])
+	}
+	if totals[1].StudentID != nil || totals[1].SeatNumber == nil || *totals[1].SeatNumber != 12 {
+		t.Errorf("unexpected guest total: %+v", totals[1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
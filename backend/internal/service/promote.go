@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+// PromoteGuest enrolls the guest in a seat of the class's current session as a new student and notifies
+// the class dashboards. In one transaction it creates the student, enrolls them with a preferred seat,
+// and hands them the guest's attendance record and point ledger entries of the session, so they keep
+// their seat, join time and points. Returns ErrNotInSession if no guest sits in req.SeatNumber.
+// The guest's session token stops working; joining again under the student's name issues a new one.
+func PromoteGuest(db *gorm.DB, class *model.Class, req model.GuestPromotionRequest) (*model.StudentWithClassPreferredSeat, error) {
+	if req.SeatNumber <= 0 {
+		return nil, ErrNotInSession
+	}
+	layout, err := GetSeatingLayout(db, class)
+	if err != nil {
+		return nil, err
+	}
+
+	var enrolled *model.StudentWithClassPreferredSeat
+	var record model.AttendanceRecord
+	err = db.Transaction(func(tx *gorm.DB) error {
+		session, err := GetCurrentSession(tx, class.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("session_id = ? AND student_id IS NULL AND seat_number = ? AND left_at IS NULL", session.ID, req.SeatNumber).
+			First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotInSession
+			}
+			return err
+		}
+
+		student := &model.Student{Name: req.Name, OwnerID: class.OwnerID}
+		if CleanStudentName(student.Name) == "" {
+			student.Name = record.Name
+		}
+		if err := CreateStudent(tx, student); err != nil {
+			return err
+		}
+		preferredSeatNumber := req.PreferredSeatNumber
+		if preferredSeatNumber == 0 {
+			preferredSeatNumber = record.SeatNumber
+		}
+		preferredSeat, err := enrollStudent(tx, class, layout, student.ID, preferredSeatNumber)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&record).Updates(map[string]interface{}{"student_id": student.ID, "name": student.Name, "name_key": student.NameKey}).Error; err != nil {
+			return err
+		}
+		record.StudentID = &student.ID
+		record.Name = student.Name
+		if err := tx.Model(&model.PointEvent{}).
+			Where("session_id = ? AND student_id IS NULL AND seat_number = ? AND attendance_id IS NULL", session.ID, record.SeatNumber).
+			Update("student_id", student.ID).Error; err != nil {
+			return err
+		}
+
+		enrolled = &model.StudentWithClassPreferredSeat{
+			ID:                  student.ID,
+			Name:                student.Name,
+			ClassID:             class.ID,
+			PreferredSeatNumber: preferredSeat.PreferredSeatNumber,
+			CreatedAt:           preferredSeat.CreatedAt,
+			UpdatedAt:           preferredSeat.UpdatedAt,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	BroadcastEvent(class.PublicID, model.GuestPromotedEvent{
+		SessionID:           record.SessionID,
+		StudentID:           enrolled.ID,
+		Name:                enrolled.Name,
+		SeatNumber:          record.SeatNumber,
+		PreferredSeatNumber: enrolled.PreferredSeatNumber,
+	})
+	return enrolled, nil
+}
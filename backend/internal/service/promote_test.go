@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestPromoteGuest(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", TotalCapacity: 10}
+	expectNoSeatingLayout(mock, "class-1")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE session_id = \$1 AND student_id IS NULL AND seat_number = \$2 AND left_at IS NULL .* FOR UPDATE`).
+		WithArgs(uint(3), 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "class_id", "student_id", "name", "seat_number"}).
+			AddRow(9, 3, "class-1", nil, "Guest", 4))
+	mock.ExpectQuery(`INSERT INTO "students"`).
+		WithArgs("Nora Lee", "nora lee", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+	mock.ExpectQuery(`INSERT INTO "student_preferred_seats"`).
+		WithArgs(uint(21), "class-1", 4, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(30, nil))
+	mock.ExpectExec(`UPDATE "attendance_records" SET "name"=\$1,"name_key"=\$2,"student_id"=\$3,"updated_at"=\$4 WHERE "id" = \$5`).
+		WithArgs("Nora Lee", "nora lee", uint(21), sqlmock.AnyArg(), uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "point_events" SET "student_id"=\$1 WHERE session_id = \$2 AND student_id IS NULL AND seat_number = \$3`).
+		WithArgs(uint(21), uint(3), 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	enrolled, err := service.PromoteGuest(db, class, model.GuestPromotionRequest{SeatNumber: 4, Name: " Nora  Lee "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if enrolled.ID != 21 || enrolled.Name != "Nora Lee" || enrolled.PreferredSeatNumber != 4 {
+		t.Errorf("unexpected enrolled student: %+v", enrolled)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPromoteGuest_NotInSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", TotalCapacity: 10}
+	expectNoSeatingLayout(mock, "class-1")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE session_id = \$1 AND student_id IS NULL AND seat_number = \$2 AND left_at IS NULL .* FOR UPDATE`).
+		WithArgs(uint(3), 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err := service.PromoteGuest(db, class, model.GuestPromotionRequest{SeatNumber: 4})
+	if !errors.Is(err, service.ErrNotInSession) {
+		t.Errorf("expected ErrNotInSession, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
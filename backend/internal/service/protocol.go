@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/pkg/jsonschema"
+)
+
+// ErrInvalidClientMessage is wrapped by errors describing why a client message was rejected.
+var ErrInvalidClientMessage = errors.New("invalid client message")
+
+// serverEvents lists an example of every server-to-client event, in the order they appear in the schema.
+var serverEvents = []model.Event{
+	model.ClassState{},
+	model.ResyncRequiredEvent{},
+	model.StudentJoinedEvent{},
+	model.StudentLeftEvent{},
+	model.PointsChangedEvent{},
+	model.GroupChangedEvent{},
+	model.SessionChangedEvent{Type: model.EventSessionStarted},
+	model.SessionChangedEvent{Type: model.EventSessionPaused},
+	model.SessionChangedEvent{Type: model.EventSessionResumed},
+	model.SessionChangedEvent{Type: model.EventSessionEnded},
+	model.JoinLockEvent{},
+	model.StudentMovedEvent{},
+	model.GuestPromotedEvent{},
+	model.ClassFullEvent{},
+	model.JoinRequestEvent{Type: model.EventJoinRequested},
+	model.JoinRequestEvent{Type: model.EventJoinDecided},
+	model.PongEvent{},
+	model.AckEvent{},
+	model.ErrorEvent{},
+}
+
+// ParseClientMessage decodes and validates a message sent by a dashboard.
+// The envelope and its data must match the current protocol exactly; unknown fields are rejected.
+// The returned message is set whenever the envelope could be decoded, so a rejection can echo its request ID.
+func ParseClientMessage(raw []byte) (*model.ClientMessage, model.ClientPayload, error) {
+	var message model.ClientMessage
+	if err := decodeStrict(raw, &message); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidClientMessage, err)
+	}
+	if message.Version != model.ProtocolVersion {
+		return &message, nil, fmt.Errorf("%w: unsupported protocol version %d, expected %d",
+			ErrInvalidClientMessage, message.Version, model.ProtocolVersion)
+	}
+
+	command, ok := clientCommands[message.Type]
+	if !ok {
+		return &message, nil, fmt.Errorf("%w: unknown message type %q", ErrInvalidClientMessage, message.Type)
+	}
+	payload := command.newPayload()
+	data := message.Data
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	if err := decodeStrict(data, payload); err != nil {
+		return &message, nil, fmt.Errorf("%w: invalid %s data: %v", ErrInvalidClientMessage, message.Type, err)
+	}
+	if err := payload.Validate(); err != nil {
+		return &message, nil, fmt.Errorf("%w: invalid %s data: %v", ErrInvalidClientMessage, message.Type, err)
+	}
+	return &message, payload, nil
+}
+
+// decodeStrict decodes a single JSON value into v, rejecting unknown fields and trailing data.
+func decodeStrict(raw []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// WebSocketProtocolSchema returns a JSON Schema describing every message of the current protocol version,
+// generated from the Go event and payload types. Server messages are under $defs.ServerMessage and
+// client messages under $defs.ClientMessage.
+func WebSocketProtocolSchema() jsonschema.Schema {
+	generator := jsonschema.NewGenerator()
+
+	var serverMessages []jsonschema.Schema
+	for _, event := range serverEvents {
+		serverMessages = append(serverMessages, jsonschema.Schema{
+			"type": "object",
+			"properties": jsonschema.Schema{
+				"version":   jsonschema.Schema{"const": model.ProtocolVersion},
+				"type":      jsonschema.Schema{"const": event.EventType()},
+				"classId":   jsonschema.Schema{"type": "string"},
+				"seq":       jsonschema.Schema{"type": "integer", "minimum": 1},
+				"data":      generator.Ref(event),
+				"timestamp": jsonschema.Schema{"type": "string", "format": "date-time"},
+			},
+			"required":             []string{"version", "type", "classId", "data", "timestamp"},
+			"additionalProperties": false,
+		})
+	}
+
+	var clientMessages []jsonschema.Schema
+	for _, messageType := range sortedKeys(clientCommands) {
+		clientMessages = append(clientMessages, jsonschema.Schema{
+			"type": "object",
+			"properties": jsonschema.Schema{
+				"version":   jsonschema.Schema{"const": model.ProtocolVersion},
+				"type":      jsonschema.Schema{"const": messageType},
+				"requestId": jsonschema.Schema{"type": "string"},
+				"data":      generator.Ref(clientCommands[messageType].newPayload()),
+			},
+			"required":             []string{"version", "type"},
+			"additionalProperties": false,
+		})
+	}
+
+	defs := generator.Defs()
+	defs["ServerMessage"] = jsonschema.Schema{"oneOf": serverMessages}
+	defs["ClientMessage"] = jsonschema.Schema{"oneOf": clientMessages}
+	return jsonschema.Schema{
+		"$schema": jsonschema.Draft,
+		"title":   fmt.Sprintf("ClassSwift WebSocket protocol v%d", model.ProtocolVersion),
+		"anyOf": []jsonschema.Schema{
+			{"$ref": "#/$defs/ServerMessage"},
+			{"$ref": "#/$defs/ClientMessage"},
+		},
+		"$defs": defs,
+	}
+}
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestParseClientMessage(t *testing.T) {
+	message, payload, err := service.ParseClientMessage([]byte(`{"version": 1, "type": "ping", "requestId": "r1"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if message.RequestID != "r1" {
+		t.Errorf("expected request ID r1, got %q", message.RequestID)
+	}
+	if _, ok := payload.(*model.PingPayload); !ok {
+		t.Errorf("expected ping payload, got %T", payload)
+	}
+}
+
+func TestParseClientMessage_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		wantRequestID string
+	}{
+		{name: "not JSON", raw: `hello`},
+		{name: "unknown envelope field", raw: `{"version": 1, "type": "ping", "extra": true}`},
+		{name: "wrong version", raw: `{"version": 2, "type": "ping", "requestId": "r2"}`, wantRequestID: "r2"},
+		{name: "missing version", raw: `{"type": "ping", "requestId": "r3"}`, wantRequestID: "r3"},
+		{name: "unknown type", raw: `{"version": 1, "type": "launch", "requestId": "r4"}`, wantRequestID: "r4"},
+		{name: "unknown data field", raw: `{"version": 1, "type": "ping", "requestId": "r5", "data": {"x": 1}}`, wantRequestID: "r5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, _, err := service.ParseClientMessage([]byte(tt.raw))
+			if !errors.Is(err, service.ErrInvalidClientMessage) {
+				t.Fatalf("expected ErrInvalidClientMessage, got %v", err)
+			}
+			if tt.wantRequestID != "" && (message == nil || message.RequestID != tt.wantRequestID) {
+				t.Errorf("expected request ID %q to be recovered, got %+v", tt.wantRequestID, message)
+			}
+		})
+	}
+}
+
+func TestWebSocketProtocolSchema(t *testing.T) {
+	schema := service.WebSocketProtocolSchema()
+
+	// The schema must be valid JSON and describe every event type
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	var decoded struct {
+		Defs map[string]struct {
+			OneOf []struct {
+				Properties struct {
+					Type struct {
+						Const string `json:"const"`
+					} `json:"type"`
+				} `json:"properties"`
+			} `json:"oneOf"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	types := map[string]bool{}
+	for _, message := range decoded.Defs["ServerMessage"].OneOf {
+		types[message.Properties.Type.Const] = true
+	}
+	for _, want := range []string{
+		model.EventClassState, model.EventResyncRequired, model.EventStudentJoined, model.EventStudentLeft,
+		model.EventPointsChanged, model.EventGroupChanged, model.EventSessionStarted, model.EventSessionEnded,
+		model.EventError,
+	} {
+		if !types[want] {
+			t.Errorf("expected server message %s in schema", want)
+		}
+	}
+	if len(decoded.Defs["ClientMessage"].OneOf) == 0 {
+		t.Error("expected client messages in schema")
+	}
+	if _, ok := decoded.Defs["PointsChangedEvent"]; !ok {
+		t.Error("expected event payload definitions in schema")
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+var (
+	// ErrNotInSession is returned when moving a student or guest who has not joined the current session.
+	ErrNotInSession = errors.New("student has not joined the current session")
+	// ErrSeatOccupied is returned when moving someone into a seat taken by another attendee of the session.
+	ErrSeatOccupied = errors.New("seat is occupied by another student in this session")
+)
+
+// MoveStudentSeat moves a student, or the guest in cmd.FromSeat, to another seat in the class's current session
+// and notifies the class dashboards. A guest's points follow them, since guest ledger entries are keyed by seat.
+func MoveStudentSeat(db *gorm.DB, class *model.Class, cmd model.MoveStudentCommand) (*model.AttendanceRecord, error) {
+	layout, err := GetSeatingLayout(db, class)
+	if err != nil {
+		return nil, err
+	}
+	if !layout.HasSeat(cmd.ToSeat) {
+		return nil, ErrInvalidSeatNumber
+	}
+
+	var record model.AttendanceRecord
+	var fromSeat int
+	err = db.Transaction(func(tx *gorm.DB) error {
+		session, err := GetCurrentSession(tx, class.ID)
+		if err != nil {
+			return err
+		}
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("session_id = ? AND left_at IS NULL", session.ID)
+		if cmd.StudentID != nil {
+			query = query.Where("student_id = ?", *cmd.StudentID)
+		} else {
+			query = query.Where("student_id IS NULL AND seat_number = ?", *cmd.FromSeat)
+		}
+		if err := query.First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotInSession
+			}
+			return err
+		}
+		fromSeat = record.SeatNumber
+		if fromSeat == cmd.ToSeat {
+			return nil
+		}
+
+		var occupied int64
+		if err := tx.Model(&model.AttendanceRecord{}).
+			Where("session_id = ? AND seat_number = ? AND left_at IS NULL AND id <> ?", session.ID, cmd.ToSeat, record.ID).
+			Count(&occupied).Error; err != nil {
+			return err
+		}
+		if occupied > 0 {
+			return ErrSeatOccupied
+		}
+
+		if err := tx.Model(&record).Update("seat_number", cmd.ToSeat).Error; err != nil {
+			if constraintViolation(err) == "unique_attendance_seat_per_session" {
+				return ErrSeatOccupied
+			}
+			return err
+		}
+		record.SeatNumber = cmd.ToSeat
+		if record.StudentID == nil && fromSeat > 0 {
+			if err := tx.Model(&model.PointEvent{}).
+				Where("session_id = ? AND student_id IS NULL AND seat_number = ? AND attendance_id IS NULL", session.ID, fromSeat).
+				Update("seat_number", cmd.ToSeat).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if fromSeat != cmd.ToSeat {
+		BroadcastEvent(class.PublicID, model.StudentMovedEvent{
+			StudentID: record.StudentID,
+			Name:      record.Name,
+			FromSeat:  fromSeat,
+			ToSeat:    cmd.ToSeat,
+		})
+	}
+	return &record, nil
+}
+
+// assignSeat picks the seat of someone joining a session: their preferred seat if it is free,
+// otherwise the first free seat in the order of the class's seat policy. Seats preferred by enrolled
+// students who have not joined yet are kept for them while any other seat is free.
+// It returns 0 when every seat of the layout is taken. Callers hold the session row lock,
+// so concurrent joins cannot pick the same seat.
+func assignSeat(tx *gorm.DB, class *model.Class, sessionID uint, preferred int) (int, error) {
+	layout, err := GetSeatingLayout(tx, class)
+	if err != nil {
+		return 0, err
+	}
+
+	var occupied []int
+	if err := tx.Model(&model.AttendanceRecord{}).
+		Where("session_id = ? AND seat_number > 0 AND left_at IS NULL", sessionID).
+		Pluck("seat_number", &occupied).Error; err != nil {
+		return 0, err
+	}
+	taken := make(map[int]bool, len(occupied))
+	for _, seat := range occupied {
+		taken[seat] = true
+	}
+	if preferred > 0 && layout.HasSeat(preferred) && !taken[preferred] {
+		return preferred, nil
+	}
+
+	var reserved []int
+	if err := tx.Model(&model.StudentPreferredSeat{}).
+		Where("class_id = ?", class.ID).
+		Where("NOT EXISTS (SELECT 1 FROM attendance_records ar WHERE ar.session_id = ? AND ar.student_id = student_preferred_seats.student_id)", sessionID).
+		Pluck("preferred_seat_number", &reserved).Error; err != nil {
+		return 0, err
+	}
+	held := make(map[int]bool, len(reserved))
+	for _, seat := range reserved {
+		held[seat] = true
+	}
+	return pickSeat(seatAssignmentOrder(layout), taken, held), nil
+}
+
+// pickSeat returns the first seat in order that is neither taken nor held, falling back to
+// the first seat that is only held, or 0 if every seat is taken.
+func pickSeat(order []int, taken, held map[int]bool) int {
+	fallback := 0
+	for _, seat := range order {
+		if taken[seat] {
+			continue
+		}
+		if !held[seat] {
+			return seat
+		}
+		if fallback == 0 {
+			fallback = seat
+		}
+	}
+	return fallback
+}
+
+// seatAssignmentOrder lists a layout's seats in the order its seat policy fills them.
+func seatAssignmentOrder(layout *model.SeatingLayout) []int {
+	switch layout.SeatPolicy {
+	case model.SeatPolicyFrontFirst:
+		// Columns ordered by distance from the middle of the row, left before right on ties
+		columns := make([]int, layout.Columns)
+		for c := range columns {
+			columns[c] = c
+		}
+		sort.SliceStable(columns, func(i, j int) bool {
+			return abs(2*columns[i]-(layout.Columns-1)) < abs(2*columns[j]-(layout.Columns-1))
+		})
+		var order []int
+		for row := 0; row < layout.Rows; row++ {
+			for _, c := range columns {
+				if seat := row*layout.Columns + c + 1; layout.HasSeat(seat) {
+					order = append(order, seat)
+				}
+			}
+		}
+		return order
+	case model.SeatPolicyFillByZone:
+		var order []int
+		inZone := make(map[int]bool)
+		for _, zone := range layout.Zones {
+			for _, seat := range zone.Seats {
+				if layout.HasSeat(seat) && !inZone[seat] {
+					order = append(order, seat)
+					inZone[seat] = true
+				}
+			}
+		}
+		for _, seat := range layout.Seats() {
+			if !inZone[seat] {
+				order = append(order, seat)
+			}
+		}
+		return order
+	default:
+		return layout.Seats()
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"classswift-backend/internal/model"
+)
+
+func TestSeatAssignmentOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout model.SeatingLayout
+		want   []int
+	}{
+		{
+			name:   "next free",
+			layout: model.SeatingLayout{Rows: 2, Columns: 3, DisabledSeats: []int{2}, SeatPolicy: model.SeatPolicyNextFree},
+			want:   []int{1, 3, 4, 5, 6},
+		},
+		{
+			name:   "front first",
+			layout: model.SeatingLayout{Rows: 2, Columns: 4, DisabledSeats: []int{3}, SeatPolicy: model.SeatPolicyFrontFirst},
+			want:   []int{2, 1, 4, 6, 7, 5, 8},
+		},
+		{
+			name: "fill by zone",
+			layout: model.SeatingLayout{Rows: 2, Columns: 3, SeatPolicy: model.SeatPolicyFillByZone, Zones: []model.SeatZone{
+				{Name: "Lab", Seats: []int{6, 5}},
+				{Name: "Window", Seats: []int{1}},
+			}},
+			want: []int{6, 5, 1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		if got := seatAssignmentOrder(&tt.layout); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPickSeat(t *testing.T) {
+	order := []int{1, 2, 3}
+
+	if seat := pickSeat(order, map[int]bool{1: true}, map[int]bool{2: true}); seat != 3 {
+		t.Errorf("expected the first seat neither taken nor held, got %d", seat)
+	}
+	if seat := pickSeat(order, map[int]bool{1: true}, map[int]bool{2: true, 3: true}); seat != 2 {
+		t.Errorf("expected a held seat once every other seat is taken, got %d", seat)
+	}
+	if seat := pickSeat(order, map[int]bool{1: true, 2: true, 3: true}, nil); seat != 0 {
+		t.Errorf("expected no seat in a full layout, got %d", seat)
+	}
+}
@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestMoveStudentSeat_InvalidSeat(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(5)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", TotalCapacity: 10}
+	expectNoSeatingLayout(mock, "class-1")
+
+	_, err := service.MoveStudentSeat(db, class, model.MoveStudentCommand{StudentID: &studentID, ToSeat: 11})
+	if !errors.Is(err, service.ErrInvalidSeatNumber) {
+		t.Errorf("expected ErrInvalidSeatNumber, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestMoveStudentSeat_Occupied(t *testing.T) {
+	db, mock := setupMockDB(t)
+	studentID := uint(5)
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", TotalCapacity: 10}
+	expectNoSeatingLayout(mock, "class-1")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND student_id = \$2 ORDER BY "attendance_records"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs(uint(3), studentID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number"}).AddRow(9, 3, studentID, "Philip", 4))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" WHERE session_id = \$1 AND seat_number = \$2 AND left_at IS NULL AND id <> \$3`).
+		WithArgs(uint(3), 6, uint(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	_, err := service.MoveStudentSeat(db, class, model.MoveStudentCommand{StudentID: &studentID, ToSeat: 6})
+	if !errors.Is(err, service.ErrSeatOccupied) {
+		t.Errorf("expected ErrSeatOccupied, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestMoveStudentSeat_GuestPointsFollow(t *testing.T) {
+	db, mock := setupMockDB(t)
+	fromSeat := 4
+	class := &model.Class{ID: "class-1", PublicID: "PUB1", TotalCapacity: 10}
+	expectNoSeatingLayout(mock, "class-1")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE \(session_id = \$1 AND left_at IS NULL\) AND \(student_id IS NULL AND seat_number = \$2\)`).
+		WithArgs(uint(3), fromSeat, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number"}).AddRow(9, 3, nil, "Guest", 4))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`UPDATE "attendance_records" SET "seat_number"=\$1,"updated_at"=\$2 WHERE "id" = \$3`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "point_events" SET "seat_number"=\$1 WHERE session_id = \$2 AND student_id IS NULL AND seat_number = \$3`).
+		WithArgs(6, uint(3), fromSeat).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	record, err := service.MoveStudentSeat(db, class, model.MoveStudentCommand{FromSeat: &fromSeat, ToSeat: 6})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.SeatNumber != 6 {
+		t.Errorf("expected guest in seat 6, got %d", record.SeatNumber)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/pkg/logger"
+)
+
+var (
+	// ErrNoActiveSession is returned when a class has no session that has not ended.
+	ErrNoActiveSession = errors.New("class has no active session")
+	// ErrSessionAlreadyActive is returned when starting a session while another one is still open.
+	ErrSessionAlreadyActive = errors.New("class already has an active session")
+	// ErrInvalidSessionTransition is returned when a session cannot move to the requested status.
+	ErrInvalidSessionTransition = errors.New("session cannot change to the requested status")
+)
+
+// GetCurrentSession fetches the open (active or paused) session of a class.
+// Returns ErrNoActiveSession if every session of the class has ended.
+func GetCurrentSession(db *gorm.DB, classID string) (*model.ClassSession, error) {
+	var session model.ClassSession
+	result := db.Where("class_id = ? AND status <> ?", classID, model.SessionStatusEnded).First(&session)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrNoActiveSession
+		}
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// GetSession fetches a session of a class by its ID.
+func GetSession(db *gorm.DB, classID string, sessionID uint) (*model.ClassSession, error) {
+	var session model.ClassSession
+	result := db.Where("id = ? AND class_id = ?", sessionID, classID).First(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// ListSessions fetches the session history of a class, most recent first.
+func ListSessions(db *gorm.DB, classID string) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	result := db.Where("class_id = ?", classID).Order("started_at DESC").Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// StartSession opens a new session for a class, set to lock joining after the class's auto-lock minutes.
+// The class dashboards are sent a join_lock_changed event when the automatic lock takes effect.
+// Returns ErrSessionAlreadyActive if the class already has an open session.
+func StartSession(db *gorm.DB, classID string) (*model.ClassSession, error) {
+	var session *model.ClassSession
+	var class model.Class
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Lock the class row so two teachers cannot start sessions concurrently
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", classID).First(&class).Error; err != nil {
+			return err
+		}
+
+		if _, err := GetCurrentSession(tx, classID); err == nil {
+			return ErrSessionAlreadyActive
+		} else if !errors.Is(err, ErrNoActiveSession) {
+			return err
+		}
+
+		session = &model.ClassSession{
+			ClassID:   classID,
+			Status:    model.SessionStatusActive,
+			StartedAt: time.Now(),
+		}
+		if class.AutoLockMinutes > 0 {
+			lockAt := session.StartedAt.Add(time.Duration(class.AutoLockMinutes) * time.Minute)
+			session.JoinsLockAt = &lockAt
+		}
+		return tx.Create(session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if session.JoinsLockAt != nil {
+		scheduleAutoLock(db, class.PublicID, session.ID, *session.JoinsLockAt)
+	}
+	return session, nil
+}
+
+// PauseSession pauses the active session of a class.
+func PauseSession(db *gorm.DB, classID string) (*model.ClassSession, error) {
+	return transitionSession(db, classID, func(session *model.ClassSession, now time.Time) error {
+		if session.Status != model.SessionStatusActive {
+			return ErrInvalidSessionTransition
+		}
+		session.Status = model.SessionStatusPaused
+		session.PausedAt = &now
+		return nil
+	})
+}
+
+// ResumeSession resumes the paused session of a class.
+func ResumeSession(db *gorm.DB, classID string) (*model.ClassSession, error) {
+	return transitionSession(db, classID, func(session *model.ClassSession, now time.Time) error {
+		if session.Status != model.SessionStatusPaused {
+			return ErrInvalidSessionTransition
+		}
+		session.Status = model.SessionStatusActive
+		session.PausedAt = nil
+		return nil
+	})
+}
+
+// EndSession ends the open session of a class, whether active or paused.
+func EndSession(db *gorm.DB, classID string) (*model.ClassSession, error) {
+	return transitionSession(db, classID, func(session *model.ClassSession, now time.Time) error {
+		session.Status = model.SessionStatusEnded
+		session.PausedAt = nil
+		session.EndedAt = &now
+		return nil
+	})
+}
+
+// JoinsLocked reports whether joining the open session of a class is refused right now.
+// A class without an open session is not locked.
+func JoinsLocked(db *gorm.DB, classID string) (bool, error) {
+	session, err := GetCurrentSession(db, classID)
+	if errors.Is(err, ErrNoActiveSession) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return session.JoinsLockedAt(time.Now()), nil
+}
+
+// ScheduleAutoLocks arranges the join_lock_changed events of the open sessions whose automatic lock
+// is still to come, such as after a server restart.
+func ScheduleAutoLocks(db *gorm.DB) error {
+	var sessions []model.ClassSession
+	if err := db.Where("status <> ? AND joins_locked = ? AND joins_lock_at > ?", model.SessionStatusEnded, false, time.Now()).
+		Find(&sessions).Error; err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		var class model.Class
+		if err := db.Select("public_id").Where("id = ?", session.ClassID).First(&class).Error; err != nil {
+			return err
+		}
+		scheduleAutoLock(db, class.PublicID, session.ID, *session.JoinsLockAt)
+	}
+	return nil
+}
+
+// scheduleAutoLock locks a session at lockAt and announces it, unless by then the session has ended,
+// been locked by the teacher or had its automatic lock cancelled. The lock is only set if it is not
+// already, so of the replicas that scheduled it, exactly one announces it.
+func scheduleAutoLock(db *gorm.DB, classPublicID string, sessionID uint, lockAt time.Time) {
+	time.AfterFunc(time.Until(lockAt), func() {
+		result := db.Model(&model.ClassSession{}).
+			Where("id = ? AND status <> ? AND joins_locked = ? AND joins_lock_at <= ?", sessionID, model.SessionStatusEnded, false, time.Now()).
+			Update("joins_locked", true)
+		if result.Error != nil {
+			logger.Errorf("Failed to apply the automatic lock of session %d: %v", sessionID, result.Error)
+			return
+		}
+		if result.RowsAffected != 1 {
+			return
+		}
+
+		var session model.ClassSession
+		if err := db.First(&session, sessionID).Error; err != nil {
+			logger.Errorf("Failed to load session %d after its automatic lock: %v", sessionID, err)
+			return
+		}
+		BroadcastEvent(classPublicID, model.JoinLockEvent{Session: session})
+	})
+}
+
+// LockJoins refuses further joins to the open session of a class.
+func LockJoins(db *gorm.DB, classID string) (*model.ClassSession, error) {
+	return setJoinsLocked(db, classID, true)
+}
+
+// UnlockJoins allows joins to the open session of a class again, cancelling its automatic lock.
+func UnlockJoins(db *gorm.DB, classID string) (*model.ClassSession, error) {
+	return setJoinsLocked(db, classID, false)
+}
+
+// setJoinsLocked locks the current session of a class and sets whether it refuses joins.
+func setJoinsLocked(db *gorm.DB, classID string, locked bool) (*model.ClassSession, error) {
+	var session *model.ClassSession
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		session, err = GetCurrentSession(tx.Clauses(clause.Locking{Strength: "UPDATE"}), classID)
+		if err != nil {
+			return err
+		}
+		session.JoinsLocked = locked
+		if !locked {
+			session.JoinsLockAt = nil
+		}
+		return tx.Model(session).Select("joins_locked", "joins_lock_at").Updates(session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// transitionSession locks the current session of a class, applies change and saves it.
+func transitionSession(db *gorm.DB, classID string, change func(session *model.ClassSession, now time.Time) error) (*model.ClassSession, error) {
+	var session *model.ClassSession
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		session, err = GetCurrentSession(tx.Clauses(clause.Locking{Strength: "UPDATE"}), classID)
+		if err != nil {
+			return err
+		}
+		if err := change(session, time.Now()); err != nil {
+			return err
+		}
+		return tx.Model(session).Select("status", "paused_at", "ended_at").Updates(session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,247 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestGetCurrentSession_None(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := service.GetCurrentSession(db, "class-1")
+	if !errors.Is(err, service.ErrNoActiveSession) {
+		t.Errorf("expected ErrNoActiveSession, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStartSession_AlreadyActive(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE id = \$1 ORDER BY "classes"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name"}).AddRow("class-1", "PUB1", "Test Class"))
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectRollback()
+
+	_, err := service.StartSession(db, "class-1")
+	if !errors.Is(err, service.ErrSessionAlreadyActive) {
+		t.Errorf("expected ErrSessionAlreadyActive, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStartSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE id = \$1 ORDER BY "classes"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name"}).AddRow("class-1", "PUB1", "Test Class"))
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery(`INSERT INTO "class_sessions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectCommit()
+
+	session, err := service.StartSession(db, "class-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.ID != 4 || session.Status != model.SessionStatusActive || session.StartedAt.IsZero() {
+		t.Errorf("unexpected session: %+v", session)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestResumeSession_NotPaused(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectRollback()
+
+	_, err := service.ResumeSession(db, "class-1")
+	if !errors.Is(err, service.ErrInvalidSessionTransition) {
+		t.Errorf("expected ErrInvalidSessionTransition, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestEndSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusPaused))
+	mock.ExpectExec(`UPDATE "class_sessions" SET "status"=\$1,"paused_at"=\$2,"ended_at"=\$3,"updated_at"=\$4 WHERE "id" = \$5`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	session, err := service.EndSession(db, "class-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != model.SessionStatusEnded || session.EndedAt == nil || session.PausedAt != nil {
+		t.Errorf("unexpected session: %+v", session)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStartSession_AutoLock(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "classes" WHERE id = \$1 ORDER BY "classes"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "auto_lock_minutes"}).AddRow("class-1", "PUB1", "Test Class", 10))
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery(`INSERT INTO "class_sessions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectCommit()
+
+	session, err := service.StartSession(db, "class-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.JoinsLockAt == nil || !session.JoinsLockAt.Equal(session.StartedAt.Add(10*time.Minute)) {
+		t.Errorf("expected joins to lock 10 minutes after the start, got %+v", session)
+	}
+	if session.JoinsLockedAt(session.StartedAt) || !session.JoinsLockedAt(session.StartedAt.Add(10*time.Minute)) {
+		t.Errorf("expected joins to lock only once the auto-lock time passes")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUnlockJoins(t *testing.T) {
+	db, mock := setupMockDB(t)
+	lockAt := time.Now().Add(-time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2 ORDER BY "class_sessions"\."id" LIMIT (\$\d+|1) FOR UPDATE`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status", "joins_locked", "joins_lock_at"}).
+			AddRow(3, "class-1", model.SessionStatusActive, true, lockAt))
+	mock.ExpectExec(`UPDATE "class_sessions" SET "joins_locked"=\$1,"joins_lock_at"=\$2,"updated_at"=\$3 WHERE "id" = \$4`).
+		WithArgs(false, nil, sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	session, err := service.UnlockJoins(db, "class-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.JoinsLockedAt(time.Now()) {
+		t.Errorf("expected joins to be unlocked, got %+v", session)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestScheduleAutoLocks(t *testing.T) {
+	db, mock := setupMockDB(t)
+	_, conn := connectClient(t, "PUB1", 1)
+	lockAt := time.Now().Add(50 * time.Millisecond)
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE status <> \$1 AND joins_locked = \$2 AND joins_lock_at > \$3`).
+		WithArgs(model.SessionStatusEnded, false, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status", "joins_lock_at"}).
+			AddRow(3, "class-1", model.SessionStatusActive, lockAt))
+	mock.ExpectQuery(`SELECT "public_id" FROM "classes" WHERE id = \$1`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"public_id"}).AddRow("PUB1"))
+	// The lock is set when due, and the session reloaded to announce it
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "class_sessions" SET "joins_locked"=\$1,"updated_at"=\$2 WHERE id = \$3 AND status <> \$4 AND joins_locked = \$5 AND joins_lock_at <= \$6`).
+		WithArgs(true, sqlmock.AnyArg(), uint(3), model.SessionStatusEnded, false, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE "class_sessions"\."id" = \$1`).
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status", "joins_locked", "joins_lock_at"}).
+			AddRow(3, "class-1", model.SessionStatusActive, true, lockAt))
+
+	if err := service.ScheduleAutoLocks(db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := conn.waitForMessage(t, model.EventJoinLock).Data.(map[string]interface{})
+	if session := data["session"].(map[string]interface{}); session["id"] != float64(3) {
+		t.Errorf("unexpected join_lock_changed event: %v", data)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestScheduleAutoLocks_AlreadyLocked(t *testing.T) {
+	db, mock := setupMockDB(t)
+	_, conn := connectClient(t, "PUB1", 1)
+	lockAt := time.Now().Add(50 * time.Millisecond)
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE status <> \$1 AND joins_locked = \$2 AND joins_lock_at > \$3`).
+		WithArgs(model.SessionStatusEnded, false, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status", "joins_lock_at"}).
+			AddRow(3, "class-1", model.SessionStatusActive, lockAt))
+	mock.ExpectQuery(`SELECT "public_id" FROM "classes" WHERE id = \$1`).
+		WithArgs("class-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"public_id"}).AddRow("PUB1"))
+	// Another replica set the lock first, so this one does not announce it again
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "class_sessions" SET "joins_locked"=\$1,"updated_at"=\$2 WHERE id = \$3`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := service.ScheduleAutoLocks(db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for mock.ExpectationsWereMet() != nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	for _, message := range conn.messages {
+		if message.Type == model.EventJoinLock {
+			t.Errorf("expected no join_lock_changed event, got %v", message.Data)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
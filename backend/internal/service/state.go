@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+)
+
+// GetClassState builds the live roster of a class's current session: everyone who has joined,
+// their seats, their point balances and the session's groups. A class without an open session has an empty roster.
+// The WebSocket sequence number is read first, so replaying from it never misses a change.
+func GetClassState(db *gorm.DB, class *model.Class) (*model.ClassState, error) {
+	state := &model.ClassState{
+		ClassID:  class.PublicID,
+		Seq:      LatestClassSeq(class.PublicID),
+		Students: []model.LiveStudent{},
+		Groups:   []model.StudentGroup{},
+	}
+
+	session, err := GetCurrentSession(db, class.ID)
+	if errors.Is(err, ErrNoActiveSession) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state.Session = session
+
+	records, err := ListAttending(db, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := GetPointTotals(db, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	studentPoints, guestPoints := pointBalances(totals)
+
+	for _, record := range records {
+		student := model.LiveStudent{
+			StudentID:  record.StudentID,
+			Name:       record.Name,
+			SeatNumber: record.SeatNumber,
+			IsGuest:    record.StudentID == nil,
+			IsLate:     record.IsLate,
+			JoinedAt:   record.JoinedAt,
+		}
+		if student.IsGuest {
+			student.Points = guestPoints[record.SeatNumber]
+		} else {
+			student.Points = studentPoints[*record.StudentID]
+		}
+		state.Students = append(state.Students, student)
+	}
+
+	groups, err := ListSessionGroups(db, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	state.Groups = groups
+	return state, nil
+}
+
+// pointBalances splits a session's point totals into enrolled students' balances, keyed by student ID,
+// and guests' balances, keyed by seat number.
+func pointBalances(totals []model.PointTotal) (map[uint]int, map[int]int) {
+	studentPoints := make(map[uint]int)
+	guestPoints := make(map[int]int)
+	for _, total := range totals {
+		switch {
+		case total.StudentID != nil:
+			studentPoints[*total.StudentID] = total.Total
+		case total.SeatNumber != nil:
+			guestPoints[*total.SeatNumber] = total.Total
+		}
+	}
+	return studentPoints, guestPoints
+}
@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestGetClassState(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "X58E9647"}
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "class_id", "status"}).AddRow(3, "class-1", model.SessionStatusActive))
+	mock.ExpectQuery(`SELECT \* FROM "attendance_records" WHERE session_id = \$1 AND left_at IS NULL ORDER BY joined_at, id`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "student_id", "name", "seat_number", "is_late"}).
+			AddRow(1, 3, 5, "Philip", 4, false).
+			AddRow(2, 3, nil, "Guest", 9, true))
+	mock.ExpectQuery(`SELECT student_id, CASE WHEN student_id IS NULL THEN seat_number END AS seat_number, SUM\(delta\) AS total FROM "point_events" WHERE session_id = \$1 AND \(student_id IS NOT NULL OR attendance_id IS NULL\) GROUP BY 1, 2`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "seat_number", "total"}).
+			AddRow(5, nil, 3).
+			AddRow(nil, 9, 2))
+	mock.ExpectQuery(`SELECT \* FROM "student_groups" WHERE session_id = \$1 ORDER BY position`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "name", "position"}).AddRow(11, 3, "Group 1", 1))
+	mock.ExpectQuery(`SELECT \* FROM "student_group_members" WHERE "student_group_members"."group_id" = \$1 ORDER BY id`).
+		WithArgs(uint(11)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "student_id", "name", "seat_number"}).
+			AddRow(1, 11, 5, "Philip", 4).
+			AddRow(2, 11, nil, "Guest", 9))
+
+	state, err := service.GetClassState(db, class)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.ClassID != "X58E9647" || state.Session == nil || state.Session.ID != 3 {
+		t.Errorf("expected current session of X58E9647, got %+v", state)
+	}
+	if len(state.Students) != 2 {
+		t.Fatalf("expected 2 students, got %d", len(state.Students))
+	}
+	if philip := state.Students[0]; philip.IsGuest || philip.Points != 3 || philip.SeatNumber != 4 {
+		t.Errorf("expected Philip in seat 4 with 3 points, got %+v", philip)
+	}
+	if guest := state.Students[1]; !guest.IsGuest || guest.Points != 2 || !guest.IsLate {
+		t.Errorf("expected late guest with 2 points, got %+v", guest)
+	}
+	if len(state.Groups) != 1 || len(state.Groups[0].Members) != 2 {
+		t.Errorf("expected one group of both students, got %+v", state.Groups)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetClassState_NoSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", PublicID: "X58E9647"}
+
+	mock.ExpectQuery(`SELECT \* FROM "class_sessions" WHERE class_id = \$1 AND status <> \$2`).
+		WithArgs("class-1", model.SessionStatusEnded, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	state, err := service.GetClassState(db, class)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Session != nil || len(state.Students) != 0 {
+		t.Errorf("expected empty roster without a session, got %+v", state)
+	}
+}
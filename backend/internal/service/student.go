@@ -0,0 +1,308 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"classswift-backend/internal/model"
+)
+
+const maxStudentNameLength = 255
+
+var (
+	// ErrInvalidStudentName mirrors the chk_name_not_empty constraint.
+	ErrInvalidStudentName = errors.New("student name must be between 1 and 255 characters")
+	// ErrInvalidSeatNumber is returned for seat numbers that are not a seat of the class's seating layout.
+	ErrInvalidSeatNumber = errors.New("seat number is not a seat in the class's seating layout")
+	// ErrSeatTaken mirrors the unique_preferred_seat_per_class constraint.
+	ErrSeatTaken = errors.New("seat is already assigned to another student in this class")
+	// ErrAlreadyEnrolled mirrors the unique_student_class_preferred constraint.
+	ErrAlreadyEnrolled = errors.New("student is already enrolled in this class")
+	// ErrClassFull mirrors the chk_student_count_valid constraint maintained by the enrollment trigger.
+	// Joining a session that has reached the class capacity fails with it too.
+	ErrClassFull = errors.New("class has reached its total capacity")
+	// ErrNotEnrolled is returned when unenrolling a student who is not enrolled in the class.
+	ErrNotEnrolled = errors.New("student is not enrolled in this class")
+)
+
+// translateEnrollmentError maps students and student_preferred_seats constraint violations to service errors.
+func translateEnrollmentError(err error) error {
+	switch constraintViolation(err) {
+	case "chk_name_not_empty":
+		return ErrInvalidStudentName
+	case "chk_preferred_seat_positive":
+		return ErrInvalidSeatNumber
+	case "unique_preferred_seat_per_class":
+		return ErrSeatTaken
+	case "unique_student_class_preferred":
+		return ErrAlreadyEnrolled
+	case "chk_student_count_valid":
+		return ErrClassFull
+	}
+	return err
+}
+
+func validateStudentName(name string) error {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > maxStudentNameLength {
+		return ErrInvalidStudentName
+	}
+	return nil
+}
+
+// GetStudent fetches a student by ID.
+func GetStudent(db *gorm.DB, studentID uint) (*model.Student, error) {
+	var student model.Student
+	result := db.Where("id = ?", studentID).First(&student)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &student, nil
+}
+
+// RenameStudent changes a student's name.
+func RenameStudent(db *gorm.DB, studentID uint, name string) (*model.Student, error) {
+	name = CleanStudentName(name)
+	if err := validateStudentName(name); err != nil {
+		return nil, err
+	}
+
+	student, err := GetStudent(db, studentID)
+	if err != nil {
+		return nil, err
+	}
+	student.Name = name
+	student.NameKey = studentNameKey(name)
+	if err := db.Model(student).Select("name", "name_key").Updates(student).Error; err != nil {
+		return nil, translateEnrollmentError(err)
+	}
+	return student, nil
+}
+
+// ManagesStudent reports whether the student belongs to the teacher and is enrolled only in their classes,
+// so that renaming, deleting or enrolling the student cannot reach into another teacher's classes.
+// Students created before ownership was recorded belong to the teacher whose classes they are enrolled in.
+// Missing students are left to the caller to report.
+func ManagesStudent(db *gorm.DB, studentID uint, teacherID uint) (bool, error) {
+	var student model.Student
+	if err := db.Select("id", "owner_id").Where("id = ?", studentID).First(&student).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if student.OwnerID != nil && *student.OwnerID != teacherID {
+		return false, nil
+	}
+
+	var enrollments struct {
+		Own    int64
+		Others int64
+	}
+	err := db.Table("student_preferred_seats AS sps").
+		Select("COUNT(*) FILTER (WHERE c.owner_id = ?) AS own, COUNT(*) FILTER (WHERE c.owner_id IS NULL OR c.owner_id <> ?) AS others", teacherID, teacherID).
+		Joins("JOIN classes c ON c.id = sps.class_id").
+		Where("sps.student_id = ?", studentID).
+		Scan(&enrollments).Error
+	if err != nil {
+		return false, err
+	}
+	return enrollments.Others == 0 && (student.OwnerID != nil || enrollments.Own > 0), nil
+}
+
+// DeleteStudent removes a student and, through the cascade, all of their enrollments.
+func DeleteStudent(db *gorm.DB, studentID uint) error {
+	result := db.Where("id = ?", studentID).Delete(&model.Student{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetClassRoster fetches the students enrolled in a class, ordered by preferred seat.
+func GetClassRoster(db *gorm.DB, classID string) ([]model.StudentWithClassPreferredSeat, error) {
+	var students []model.StudentWithClassPreferredSeat
+	result := db.Table("student_preferred_seats AS sps").
+		Select("s.id, s.name, sps.class_id, sps.preferred_seat_number, sps.created_at, sps.updated_at").
+		Joins("JOIN students s ON s.id = sps.student_id").
+		Where("sps.class_id = ?", classID).
+		Order("sps.preferred_seat_number").
+		Scan(&students)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return students, nil
+}
+
+// EnrollStudent enrolls a student in a class with a preferred seat from the class's seating layout.
+func EnrollStudent(db *gorm.DB, class *model.Class, studentID uint, seatNumber int) (*model.StudentPreferredSeat, error) {
+	layout, err := GetSeatingLayout(db, class)
+	if err != nil {
+		return nil, err
+	}
+	return enrollStudent(db, class, layout, studentID, seatNumber)
+}
+
+// enrollStudent enrolls a student in a class with a preferred seat from the given layout.
+func enrollStudent(db *gorm.DB, class *model.Class, layout *model.SeatingLayout, studentID uint, seatNumber int) (*model.StudentPreferredSeat, error) {
+	if !layout.HasSeat(seatNumber) {
+		return nil, ErrInvalidSeatNumber
+	}
+
+	preferredSeat := &model.StudentPreferredSeat{
+		StudentID:           studentID,
+		ClassID:             class.ID,
+		PreferredSeatNumber: seatNumber,
+	}
+	// Omit the associations so GORM does not try to upsert the student and class rows
+	if err := db.Omit(clause.Associations).Create(preferredSeat).Error; err != nil {
+		return nil, translateEnrollmentError(err)
+	}
+	return preferredSeat, nil
+}
+
+// UnenrollStudent removes a student's enrollment (and preferred seat) from a class.
+func UnenrollStudent(db *gorm.DB, classID string, studentID uint) error {
+	result := db.Where("class_id = ? AND student_id = ?", classID, studentID).Delete(&model.StudentPreferredSeat{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotEnrolled
+	}
+	return nil
+}
+
+// ParseRosterCSV parses "name,seat" rows from a roster CSV. A leading header row is skipped.
+// Rows that cannot be parsed are returned as import errors rather than failing the whole file.
+func ParseRosterCSV(r io.Reader) ([]model.RosterImportRow, []model.RosterImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []model.RosterImportRow
+	var rowErrors []model.RosterImportError
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, model.RosterImportError{Row: line, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if line == 1 && isRosterHeader(record) {
+			continue
+		}
+		if len(record) != 2 {
+			rowErrors = append(rowErrors, model.RosterImportError{
+				Row:   line,
+				Error: fmt.Sprintf("expected 2 columns (name, seat), got %d", len(record)),
+			})
+			continue
+		}
+
+		name := strings.TrimSpace(record[0])
+		seat, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			rowErrors = append(rowErrors, model.RosterImportError{Row: line, Name: name, Error: "seat must be a whole number"})
+			continue
+		}
+		rows = append(rows, model.RosterImportRow{Row: line, Name: name, SeatNumber: seat})
+	}
+	return rows, rowErrors, nil
+}
+
+func isRosterHeader(record []string) bool {
+	return len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "name")
+}
+
+// findRosterStudent looks up the student a roster row names among those owned by the class's teacher or
+// enrolled in one of their classes, so a teacher's import never picks up another teacher's student.
+// Returns ErrAmbiguousName if several of them have the name.
+func findRosterStudent(db *gorm.DB, class *model.Class, name string) (*model.Student, error) {
+	var students []model.Student
+	result := db.Where(`name_key = ? AND (owner_id = ? OR id IN (
+			SELECT sps.student_id FROM student_preferred_seats sps JOIN classes c ON c.id = sps.class_id
+			WHERE sps.class_id = ? OR c.owner_id = ?))`, studentNameKey(name), class.OwnerID, class.ID, class.OwnerID).
+		Order("id").Limit(2).Find(&students)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	switch len(students) {
+	case 0:
+		return nil, gorm.ErrRecordNotFound
+	case 1:
+		return &students[0], nil
+	}
+	return nil, ErrAmbiguousName
+}
+
+// ImportRoster enrolls each row's student in the class, creating a student when the name matches none
+// of the teacher's students. Each row is applied in its own transaction so one bad row does not block the rest.
+func ImportRoster(db *gorm.DB, class *model.Class, rows []model.RosterImportRow) model.RosterImportResult {
+	result := model.RosterImportResult{
+		Imported: []model.StudentWithClassPreferredSeat{},
+		Errors:   []model.RosterImportError{},
+	}
+
+	layout, err := GetSeatingLayout(db, class)
+	if err != nil {
+		for _, row := range rows {
+			result.Errors = append(result.Errors, model.RosterImportError{Row: row.Row, Name: row.Name, Error: err.Error()})
+		}
+		return result
+	}
+
+	for _, row := range rows {
+		var enrolled model.StudentWithClassPreferredSeat
+		err := db.Transaction(func(tx *gorm.DB) error {
+			student, err := findRosterStudent(tx, class, row.Name)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				student = &model.Student{Name: row.Name, OwnerID: class.OwnerID}
+				err = CreateStudent(tx, student)
+			}
+			if err != nil {
+				return err
+			}
+
+			preferredSeat, err := enrollStudent(tx, class, layout, student.ID, row.SeatNumber)
+			if err != nil {
+				return err
+			}
+			enrolled = model.StudentWithClassPreferredSeat{
+				ID:                  student.ID,
+				Name:                student.Name,
+				ClassID:             class.ID,
+				PreferredSeatNumber: preferredSeat.PreferredSeatNumber,
+				CreatedAt:           preferredSeat.CreatedAt,
+				UpdatedAt:           preferredSeat.UpdatedAt,
+			}
+			return nil
+		})
+		if err != nil {
+			result.Errors = append(result.Errors, model.RosterImportError{Row: row.Row, Name: row.Name, Error: err.Error()})
+			continue
+		}
+		result.Imported = append(result.Imported, enrolled)
+	}
+	return result
+}
@@ -0,0 +1,206 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestParseRosterCSV(t *testing.T) {
+	csvData := "Name,Seat\nPhilip,1\n\nDarrell, 2\nMaria,front\nJessica\n"
+
+	rows, rowErrors, err := service.ParseRosterCSV(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d: %+v", len(rows), rows)
+	}
+	if rows[0].Name != "Philip" || rows[0].SeatNumber != 1 || rows[0].Row != 2 {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Name != "Darrell" || rows[1].SeatNumber != 2 {
+		t.Errorf("unexpected second row: %+v", rows[1])
+	}
+	if len(rowErrors) != 2 {
+		t.Fatalf("expected 2 row errors, got %d: %+v", len(rowErrors), rowErrors)
+	}
+	if rowErrors[0].Name != "Maria" || rowErrors[0].Error != "seat must be a whole number" {
+		t.Errorf("unexpected seat error: %+v", rowErrors[0])
+	}
+	if !strings.Contains(rowErrors[1].Error, "expected 2 columns") {
+		t.Errorf("unexpected column count error: %+v", rowErrors[1])
+	}
+}
+
+func TestEnrollStudent_InvalidSeat(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30}
+
+	for _, seat := range []int{0, 31} {
+		expectNoSeatingLayout(mock, "class-1")
+		if _, err := service.EnrollStudent(db, class, 1, seat); !errors.Is(err, service.ErrInvalidSeatNumber) {
+			t.Errorf("seat %d: expected ErrInvalidSeatNumber, got %v", seat, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestEnrollStudent_SeatTaken(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30}
+
+	expectNoSeatingLayout(mock, "class-1")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "student_preferred_seats"`).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "unique_preferred_seat_per_class"})
+	mock.ExpectRollback()
+
+	if _, err := service.EnrollStudent(db, class, 1, 5); !errors.Is(err, service.ErrSeatTaken) {
+		t.Errorf("expected ErrSeatTaken, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUnenrollStudent_NotEnrolled(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "student_preferred_seats" WHERE class_id = \$1 AND student_id = \$2`).
+		WithArgs("class-1", uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := service.UnenrollStudent(db, "class-1", 9); !errors.Is(err, service.ErrNotEnrolled) {
+		t.Errorf("expected ErrNotEnrolled, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestManagesStudent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	ownerID := uint(3)
+
+	cases := []struct {
+		name          string
+		ownerID       *uint
+		own, others   int
+		teacherID     uint
+		want          bool
+		lookupEnrolls bool
+	}{
+		{"created by the teacher, not yet enrolled", &ownerID, 0, 0, 3, true, true},
+		{"created by another teacher, not yet enrolled", &ownerID, 0, 0, 4, false, false},
+		{"created by the teacher, enrolled elsewhere", &ownerID, 1, 1, 3, false, true},
+		{"unowned, enrolled only in the teacher's classes", nil, 2, 0, 3, true, true},
+		{"unowned and not enrolled", nil, 0, 0, 3, false, true},
+	}
+	for _, tc := range cases {
+		mock.ExpectQuery(`SELECT "id","owner_id" FROM "students" WHERE id = \$1`).
+			WithArgs(uint(5), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id"}).AddRow(5, tc.ownerID))
+		if tc.lookupEnrolls {
+			mock.ExpectQuery(`SELECT COUNT\(\*\) FILTER \(WHERE c\.owner_id = \$1\) AS own, COUNT\(\*\) FILTER \(WHERE c\.owner_id IS NULL OR c\.owner_id <> \$2\) AS others FROM student_preferred_seats AS sps`).
+				WithArgs(tc.teacherID, tc.teacherID, uint(5)).
+				WillReturnRows(sqlmock.NewRows([]string{"own", "others"}).AddRow(tc.own, tc.others))
+		}
+
+		manages, err := service.ManagesStudent(db, 5, tc.teacherID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if manages != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, manages)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestImportRoster_ReportsRowErrors(t *testing.T) {
+	db, mock := setupMockDB(t)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30}
+	rows := []model.RosterImportRow{
+		{Row: 2, Name: "Philip", SeatNumber: 1},
+		{Row: 3, Name: "Newcomer", SeatNumber: 1},
+	}
+
+	expectNoSeatingLayout(mock, "class-1")
+
+	// Row 2: existing student enrolled successfully
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "students" WHERE name_key = \$1 AND \(owner_id = \$2 OR id IN \(.*WHERE sps\.class_id = \$3 OR c\.owner_id = \$4\)\)\s*ORDER BY id LIMIT \$5`).
+		WithArgs("philip", nil, "class-1", nil, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Philip"))
+	mock.ExpectQuery(`INSERT INTO "student_preferred_seats"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectCommit()
+
+	// Row 3: new student created, but the seat is already taken so the row is rolled back
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "students" WHERE name_key = \$1 AND \(owner_id = \$2 OR id IN \(.*WHERE sps\.class_id = \$3 OR c\.owner_id = \$4\)\)\s*ORDER BY id LIMIT \$5`).
+		WithArgs("newcomer", nil, "class-1", nil, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`INSERT INTO "students"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(36))
+	mock.ExpectQuery(`INSERT INTO "student_preferred_seats"`).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "unique_preferred_seat_per_class"})
+	mock.ExpectRollback()
+
+	result := service.ImportRoster(db, class, rows)
+	if len(result.Imported) != 1 || result.Imported[0].Name != "Philip" || result.Imported[0].PreferredSeatNumber != 1 {
+		t.Errorf("unexpected imported rows: %+v", result.Imported)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 3 || result.Errors[0].Error != service.ErrSeatTaken.Error() {
+		t.Errorf("unexpected row errors: %+v", result.Errors)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestImportRoster_IgnoresOtherTeachersStudents(t *testing.T) {
+	db, mock := setupMockDB(t)
+	ownerID := uint(7)
+	class := &model.Class{ID: "class-1", TotalCapacity: 30, OwnerID: &ownerID}
+	rows := []model.RosterImportRow{{Row: 2, Name: "Alice", SeatNumber: 3}}
+
+	expectNoSeatingLayout(mock, "class-1")
+
+	// Another teacher's Alice is left out of the lookup, so a new Alice is created for this teacher and enrolled
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "students" WHERE name_key = \$1 AND \(owner_id = \$2 OR id IN \(.*WHERE sps\.class_id = \$3 OR c\.owner_id = \$4\)\)\s*ORDER BY id LIMIT \$5`).
+		WithArgs("alice", ownerID, "class-1", ownerID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`INSERT INTO "students"`).
+		WithArgs("Alice", "alice", ownerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	mock.ExpectQuery(`INSERT INTO "student_preferred_seats"`).
+		WithArgs(uint(42), "class-1", 3, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectCommit()
+
+	result := service.ImportRoster(db, class, rows)
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected row errors: %+v", result.Errors)
+	}
+	if len(result.Imported) != 1 || result.Imported[0].ID != 42 || result.Imported[0].Name != "Alice" {
+		t.Errorf("expected a new Alice to be enrolled, got %+v", result.Imported)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+	"classswift-backend/pkg/token"
+)
+
+// ErrInvalidToken is returned when a student session token is malformed, forged or expired.
+var ErrInvalidToken = errors.New("invalid or expired session token")
+
+// tokenIDAlphabet is the character set for token IDs.
+const tokenIDAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// NewStudentSessionClaims builds the session claims for a completed join, valid from now for ttl.
+func NewStudentSessionClaims(result *model.JoinResult, studentName string, now time.Time, ttl time.Duration) (*model.StudentSessionClaims, error) {
+	tokenID, err := randomString(tokenIDAlphabet, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &model.StudentSessionClaims{
+		Type:      model.StudentTokenType,
+		TokenID:   tokenID,
+		Name:      studentName,
+		ClassID:   result.Class.PublicID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+	if result.Student != nil {
+		claims.StudentID = &result.Student.ID
+	}
+	if result.PreferredSeat != nil {
+		claims.SeatNumber = result.PreferredSeat.PreferredSeatNumber
+	}
+	if result.Attendance != nil {
+		claims.SessionID = &result.Attendance.SessionID
+		claims.AttendanceID = &result.Attendance.ID
+		claims.SeatNumber = result.Attendance.SeatNumber
+	}
+	return claims, nil
+}
+
+// IssueStudentToken signs a session token for a completed join using the configured secret and TTL.
+func IssueStudentToken(result *model.JoinResult, studentName string) (string, *model.StudentSessionClaims, error) {
+	claims, err := NewStudentSessionClaims(result, studentName, time.Now(), config.SessionTokenTTL())
+	if err != nil {
+		return "", nil, err
+	}
+	signed, err := token.Sign(config.SessionTokenSecret(), claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// VerifyStudentToken checks a session token against the configured secret and returns its claims.
+// Teacher access tokens are rejected even though they share the signing secret.
+func VerifyStudentToken(signed string) (*model.StudentSessionClaims, error) {
+	var claims model.StudentSessionClaims
+	if err := token.Verify(config.SessionTokenSecret(), signed, &claims, time.Now()); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Type != model.StudentTokenType {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// CheckStudentSession reports whether the join a session token was issued for still stands.
+// Tokens for a session stop working once the student leaves or is removed from it or the session
+// ends, and stay invalid if they join again. Returns ErrInvalidToken otherwise.
+func CheckStudentSession(db *gorm.DB, claims *model.StudentSessionClaims) error {
+	if claims.SessionID == nil {
+		return nil
+	}
+	if claims.AttendanceID == nil {
+		return ErrInvalidToken
+	}
+	var attending int64
+	err := db.Model(&model.AttendanceRecord{}).
+		Joins("JOIN class_sessions ON class_sessions.id = attendance_records.session_id").
+		Where("attendance_records.id = ? AND attendance_records.session_id = ? AND attendance_records.left_at IS NULL AND class_sessions.status <> ?",
+			*claims.AttendanceID, *claims.SessionID, model.SessionStatusEnded).
+		Count(&attending).Error
+	if err != nil {
+		return err
+	}
+	if attending == 0 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// StudentRedirectURL appends the session token to the student app redirect URL.
+func StudentRedirectURL(baseURL string, signed string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", signed)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
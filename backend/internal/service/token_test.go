@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"classswift-backend/config"
+	"classswift-backend/internal/model"
+	"classswift-backend/internal/service"
+)
+
+func TestNewStudentSessionClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	result := &model.JoinResult{
+		Class:         &model.Class{ID: "class-1", PublicID: "PUB1"},
+		Student:       &model.Student{ID: 7, Name: "Alice"},
+		PreferredSeat: &model.StudentPreferredSeat{PreferredSeatNumber: 3},
+		Attendance:    &model.AttendanceRecord{ID: 21, SessionID: 12, SeatNumber: 3},
+	}
+
+	claims, err := service.NewStudentSessionClaims(result, "Alice", now, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.TokenID == "" {
+		t.Error("expected a token ID")
+	}
+	if claims.Type != model.StudentTokenType {
+		t.Errorf("expected student token type, got %q", claims.Type)
+	}
+	if claims.ClassID != "PUB1" || claims.Name != "Alice" || claims.SeatNumber != 3 {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if claims.StudentID == nil || *claims.StudentID != 7 {
+		t.Errorf("expected student ID 7, got %v", claims.StudentID)
+	}
+	if claims.SessionID == nil || *claims.SessionID != 12 {
+		t.Errorf("expected session ID 12, got %v", claims.SessionID)
+	}
+	if claims.AttendanceID == nil || *claims.AttendanceID != 21 {
+		t.Errorf("expected attendance ID 21, got %v", claims.AttendanceID)
+	}
+	if claims.ExpiresAt != now.Add(time.Hour).Unix() {
+		t.Errorf("expected expiry one hour after issue, got %d", claims.ExpiresAt)
+	}
+}
+
+func TestIssueAndVerifyStudentToken(t *testing.T) {
+	config.Init()
+	result := &model.JoinResult{Class: &model.Class{ID: "class-1", PublicID: "PUB1"}}
+
+	signed, issued, err := service.IssueStudentToken(result, "Guest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims, err := service.VerifyStudentToken(signed)
+	if err != nil {
+		t.Fatalf("expected token to verify, got %v", err)
+	}
+	if claims.TokenID != issued.TokenID || claims.Name != "Guest" || claims.StudentID != nil {
+		t.Errorf("unexpected verified claims: %+v", claims)
+	}
+
+	if _, err := service.VerifyStudentToken(signed + "x"); err != service.ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken for tampered token, got %v", err)
+	}
+}
+
+func TestVerifyStudentToken_TeacherToken(t *testing.T) {
+	config.Init()
+	signed, _, err := service.IssueTeacherToken(&model.Teacher{ID: 3, Email: "teacher@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.VerifyStudentToken(signed); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for a teacher token, got %v", err)
+	}
+}
+
+// expectAttendingCount expects the lookup of a token's attendance record among those still standing.
+func expectAttendingCount(mock sqlmock.Sqlmock, attendanceID, sessionID uint, count int) {
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "attendance_records" JOIN class_sessions ON class_sessions\.id = attendance_records\.session_id WHERE attendance_records\.id = \$1 AND attendance_records\.session_id = \$2 AND attendance_records\.left_at IS NULL AND class_sessions\.status <> \$3`).
+		WithArgs(attendanceID, sessionID, model.SessionStatusEnded).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestCheckStudentSession(t *testing.T) {
+	db, mock := setupMockDB(t)
+	sessionID, attendanceID := uint(3), uint(9)
+	claims := &model.StudentSessionClaims{Name: "Guest", ClassID: "PUB1", SessionID: &sessionID, AttendanceID: &attendanceID}
+
+	expectAttendingCount(mock, 9, 3, 1)
+
+	if err := service.CheckStudentSession(db, claims); err != nil {
+		t.Errorf("expected the token to stand, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCheckStudentSession_Left(t *testing.T) {
+	db, mock := setupMockDB(t)
+	sessionID, attendanceID := uint(3), uint(9)
+	claims := &model.StudentSessionClaims{Name: "Guest", ClassID: "PUB1", SessionID: &sessionID, AttendanceID: &attendanceID}
+
+	// The record has left, has been replaced by a later join, or its session has ended
+	expectAttendingCount(mock, 9, 3, 0)
+
+	if err := service.CheckStudentSession(db, claims); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken once the guest has left, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCheckStudentSession_NoAttendanceID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	sessionID := uint(3)
+	claims := &model.StudentSessionClaims{Name: "Guest", ClassID: "PUB1", SessionID: &sessionID}
+
+	if err := service.CheckStudentSession(db, claims); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for a token without its attendance record, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStudentRedirectURL(t *testing.T) {
+	got, err := service.StudentRedirectURL("https://app.example.com/class?lang=en", "abc.def")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u, _ := url.Parse(got)
+	if u.Host != "app.example.com" || u.Path != "/class" {
+		t.Errorf("unexpected redirect URL %q", got)
+	}
+	if u.Query().Get("token") != "abc.def" || u.Query().Get("lang") != "en" {
+		t.Errorf("expected token and existing query to be kept, got %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"classswift-backend/internal/model"
+)
+
+// WaitlistedError is returned when a join to a full session is put on the class's waiting list.
+// Position counts from 1 for the next joiner to be admitted.
+type WaitlistedError struct {
+	SessionID uint
+	Position  int
+}
+
+func (e *WaitlistedError) Error() string {
+	return fmt.Sprintf("class is full; waiting list position %d", e.Position)
+}
+
+// ListWaitlist fetches a session's waiting list in the order joiners will be admitted.
+func ListWaitlist(db *gorm.DB, sessionID uint) ([]model.WaitlistEntry, error) {
+	var entries []model.WaitlistEntry
+	if err := db.Where("session_id = ?", sessionID).Order("id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// admitJoin checks someone joining a session against the class capacity. Those already waiting go first:
+// a joiner is admitted while their place in the queue, or the end of the queue for a newcomer,
+// is within the seats left, and an admitted joiner leaves the waiting list.
+// A joiner who is not admitted is wait-listed when the class allows it. The returned event is set
+// only when the joiner is not admitted, with ErrClassFull or a *WaitlistedError as the error.
+func admitJoin(tx *gorm.DB, class *model.Class, sessionID uint, studentID *uint, name string) (*model.ClassFullEvent, error) {
+	attending, err := CountAttending(tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	waitlist, err := ListWaitlist(tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := studentNameKey(name)
+	position := -1
+	for i, entry := range waitlist {
+		if studentID != nil && entry.StudentID != nil && *entry.StudentID == *studentID ||
+			studentID == nil && entry.StudentID == nil && entry.NameKey == key {
+			position = i
+			break
+		}
+	}
+	queued := position
+	if queued < 0 {
+		queued = len(waitlist)
+	}
+	if queued < class.TotalCapacity-attending {
+		if position >= 0 {
+			if err := tx.Delete(&waitlist[position]).Error; err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	full := &model.ClassFullEvent{
+		SessionID:      sessionID,
+		Name:           name,
+		Capacity:       class.TotalCapacity,
+		Attending:      attending,
+		WaitlistLength: len(waitlist),
+	}
+	if !class.WaitlistWhenFull && position < 0 {
+		return full, ErrClassFull
+	}
+	if position < 0 {
+		entry := &model.WaitlistEntry{SessionID: sessionID, ClassID: class.ID, StudentID: studentID, Name: name, NameKey: key}
+		if err := tx.Create(entry).Error; err != nil {
+			return nil, err
+		}
+		full.WaitlistLength++
+	}
+	full.Waitlisted = true
+	return full, &WaitlistedError{SessionID: sessionID, Position: queued + 1}
+}
@@ -1,8 +1,6 @@
 package service
 
 import (
-	"time"
-
 	"classswift-backend/internal/model"
 	"classswift-backend/pkg/utils"
 )
@@ -10,10 +8,20 @@ import (
 // Global WebSocket manager instance
 var wsManager *utils.WebSocketManager
 
-// InitWebSocketHub initializes the global WebSocket hub
-func InitWebSocketHub() {
-	wsManager = utils.NewWebSocketManager()
-	wsManager.Start()
+// InitWebSocketHub initializes the global WebSocket hub with in-process broadcasting
+func InitWebSocketHub() error {
+	return InitWebSocketHubWithBroadcaster(utils.NewMemoryBroadcaster())
+}
+
+// InitWebSocketHubWithBroadcaster initializes the global WebSocket hub, fanning messages out through broadcaster
+// so that clients connected to other replicas sharing it also receive them
+func InitWebSocketHubWithBroadcaster(broadcaster utils.Broadcaster) error {
+	manager := utils.NewWebSocketManagerWithBroadcaster(broadcaster)
+	if err := manager.Start(); err != nil {
+		return err
+	}
+	wsManager = manager
+	return nil
 }
 
 // RegisterClient registers a WebSocket client with the hub
@@ -32,19 +40,34 @@ func UnregisterClient(client *model.Client) {
 	wsManager.UnregisterClient(client)
 }
 
+// GetWebSocketStats returns the hub's connection count and slow-consumer counters
+func GetWebSocketStats() model.WebSocketStats {
+	if wsManager == nil {
+		return model.WebSocketStats{}
+	}
+	return wsManager.Stats()
+}
 
-// BroadcastClassUpdate broadcasts general class updates
-func BroadcastClassUpdate(classID string, updateType string, data interface{}) {
+// LatestClassSeq returns the sequence number of the last WebSocket message delivered for a class, or 0 if none
+func LatestClassSeq(classID string) uint64 {
 	if wsManager == nil {
-		return
+		return 0
 	}
+	return wsManager.LatestSeq(classID)
+}
 
-	message := model.WebSocketMessage{
-		Type:      updateType,
-		ClassID:   classID,
-		Data:      data,
-		Timestamp: time.Now(),
+// SendEvent sends an event to a single connected client
+func SendEvent(client *model.Client, event model.Event) {
+	if wsManager == nil {
+		return
 	}
+	wsManager.SendToClient(client, model.NewWebSocketMessage(client.ClassID, event))
+}
 
-	wsManager.Broadcast(message)
+// BroadcastEvent broadcasts an event to every dashboard connected to a class
+func BroadcastEvent(classID string, event model.Event) {
+	if wsManager == nil {
+		return
+	}
+	wsManager.Broadcast(model.NewWebSocketMessage(classID, event))
 }
@@ -46,30 +46,23 @@ func TestUnregisterClient_NilManager(t *testing.T) {
 	UnregisterClient(client)
 }
 
-func TestBroadcastClassUpdate_NilManager(t *testing.T) {
+func TestBroadcastEvent_NilManager(t *testing.T) {
 	// Ensure wsManager is nil
 	wsManager = nil
 
-	data := map[string]interface{}{
-		"totalCapacity":  30,
-		"availableSlots": 15,
-	}
+	event := model.StudentJoinedEvent{Name: "Philip", SeatNumber: 3}
 
 	// Should not panic when wsManager is nil
-	BroadcastClassUpdate("test-class", "class_updated", data)
+	BroadcastEvent("test-class", event)
 }
 
-func TestBroadcastClassUpdate_WithManager(t *testing.T) {
+func TestBroadcastEvent_WithManager(t *testing.T) {
 	// Create a mock WebSocket manager
 	mockManager := utils.NewWebSocketManager()
 	wsManager = mockManager
 
 	testClassID := "test-class-123"
-	testUpdateType := "class_updated"
-	testData := map[string]interface{}{
-		"totalCapacity":  30,
-		"availableSlots": 10,
-	}
+	testEvent := model.StudentJoinedEvent{Name: "Philip", SeatNumber: 3}
 
 	// Start the manager to handle broadcasts
 	mockManager.Start()
@@ -78,7 +71,7 @@ func TestBroadcastClassUpdate_WithManager(t *testing.T) {
 	time.Sleep(10 * time.Millisecond)
 
 	// Should not panic with valid manager
-	BroadcastClassUpdate(testClassID, testUpdateType, testData)
+	BroadcastEvent(testClassID, testEvent)
 
 	// Verify message was sent by checking the broadcast channel
 	// We can't easily test the actual broadcast without mocking WebSocket connections
@@ -127,14 +120,11 @@ func TestWebSocketServiceIntegration(t *testing.T) {
 	RegisterClient(client)
 
 	// Broadcast a message
-	testData := map[string]interface{}{
-		"message": "test broadcast",
-	}
-	BroadcastClassUpdate("integration-test-class", "test_event", testData)
+	BroadcastEvent("integration-test-class", model.PointsChangedEvent{SessionID: 1, Delta: 1, Total: 1})
 
 	// Unregister client
 	UnregisterClient(client)
 
 	// Clean up
 	wsManager = nil
-}
\ No newline at end of file
+}
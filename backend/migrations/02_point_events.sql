@@ -0,0 +1,22 @@
+-- Point ledger for ClassSwift Teacher Dashboard
+-- Every award or deduction is stored as an event; balances are computed by summing deltas.
+
+CREATE TABLE IF NOT EXISTS point_events (
+    id SERIAL PRIMARY KEY,
+    class_id VARCHAR(255) NOT NULL,               -- Reference to class
+    student_id INTEGER,                           -- Enrolled student receiving the points (NULL for guests)
+    seat_number INTEGER,                          -- Seat receiving the points (identifies guests)
+    delta INTEGER NOT NULL,                       -- Points awarded (positive) or deducted (negative)
+    reason VARCHAR(255) NOT NULL DEFAULT '',      -- Why the points were given
+    teacher VARCHAR(255) NOT NULL DEFAULT '',     -- Teacher who recorded the event
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_point_event_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_point_event_student FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
+    CONSTRAINT chk_point_event_target CHECK (student_id IS NOT NULL OR seat_number > 0),
+    CONSTRAINT chk_point_event_delta_nonzero CHECK (delta <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_events_class_id ON point_events(class_id);
+CREATE INDEX IF NOT EXISTS idx_point_events_student_id ON point_events(student_id);
+CREATE INDEX IF NOT EXISTS idx_point_events_class_seat ON point_events(class_id, seat_number);
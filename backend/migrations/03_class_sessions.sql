@@ -0,0 +1,34 @@
+-- Class sessions for ClassSwift Teacher Dashboard
+-- A session is one lesson of a class; joins, points and groups are scoped to it.
+
+CREATE TABLE IF NOT EXISTS class_sessions (
+    id SERIAL PRIMARY KEY,
+    class_id VARCHAR(255) NOT NULL,               -- Reference to class
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, paused or ended
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paused_at TIMESTAMP,                          -- Set while the session is paused
+    ended_at TIMESTAMP,                           -- Set once the session has ended
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_session_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT chk_session_status CHECK (status IN ('active', 'paused', 'ended')),
+    CONSTRAINT chk_session_ended_at CHECK ((status = 'ended') = (ended_at IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_class_sessions_class_id ON class_sessions(class_id, started_at DESC);
+
+-- Only one session per class may be open (active or paused) at a time
+CREATE UNIQUE INDEX IF NOT EXISTS unique_open_session_per_class ON class_sessions(class_id) WHERE status <> 'ended';
+
+DROP TRIGGER IF EXISTS trigger_class_sessions_updated_at ON class_sessions;
+CREATE TRIGGER trigger_class_sessions_updated_at
+    BEFORE UPDATE ON class_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Scope the point ledger to sessions
+ALTER TABLE point_events ADD COLUMN IF NOT EXISTS session_id INTEGER;
+ALTER TABLE point_events DROP CONSTRAINT IF EXISTS fk_point_event_session;
+ALTER TABLE point_events ADD CONSTRAINT fk_point_event_session FOREIGN KEY (session_id) REFERENCES class_sessions(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_point_events_session_id ON point_events(session_id);
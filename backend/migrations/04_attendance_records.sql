@@ -0,0 +1,32 @@
+-- Attendance records for ClassSwift Teacher Dashboard
+-- One record per student (or guest name) joining a class session.
+
+CREATE TABLE IF NOT EXISTS attendance_records (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL,                  -- Reference to class session
+    class_id VARCHAR(255) NOT NULL,               -- Reference to class
+    student_id INTEGER,                           -- Enrolled student (NULL for guests)
+    name VARCHAR(255) NOT NULL,                   -- Name entered when joining
+    seat_number INTEGER NOT NULL DEFAULT 0,       -- Seat at join time (0 if unassigned)
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    is_late BOOLEAN NOT NULL DEFAULT FALSE,       -- Joined after the late threshold from session start
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_attendance_session FOREIGN KEY (session_id) REFERENCES class_sessions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_attendance_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_attendance_student FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE SET NULL,
+    CONSTRAINT chk_attendance_name_not_empty CHECK (LENGTH(TRIM(name)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_session_id ON attendance_records(session_id);
+
+-- A student, or a guest name, is recorded at most once per session
+CREATE UNIQUE INDEX IF NOT EXISTS unique_attendance_student_per_session ON attendance_records(session_id, student_id) WHERE student_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_attendance_guest_per_session ON attendance_records(session_id, LOWER(name)) WHERE student_id IS NULL;
+
+DROP TRIGGER IF EXISTS trigger_attendance_records_updated_at ON attendance_records;
+CREATE TRIGGER trigger_attendance_records_updated_at
+    BEFORE UPDATE ON attendance_records
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
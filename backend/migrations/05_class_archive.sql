@@ -0,0 +1,6 @@
+-- Class management for ClassSwift Teacher Dashboard
+-- Archived classes are kept for history but hidden from the class list.
+
+ALTER TABLE classes ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_classes_archived_at ON classes(archived_at);
@@ -0,0 +1,34 @@
+-- Teacher accounts and class ownership for ClassSwift Teacher Dashboard
+-- Only the owning teacher may view a class, its QR code and its live feed.
+
+CREATE TABLE IF NOT EXISTS teachers (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,                  -- Login email, stored lower-cased
+    name VARCHAR(255) NOT NULL,                   -- Display name
+    password_hash VARCHAR(255) NOT NULL,          -- bcrypt hash of the password
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_teacher_email UNIQUE (email),
+    CONSTRAINT chk_teacher_name_not_empty CHECK (LENGTH(TRIM(name)) > 0)
+);
+
+DROP TRIGGER IF EXISTS trigger_teachers_updated_at ON teachers;
+CREATE TRIGGER trigger_teachers_updated_at
+    BEFORE UPDATE ON teachers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE classes ADD COLUMN IF NOT EXISTS owner_id INTEGER;
+ALTER TABLE classes DROP CONSTRAINT IF EXISTS fk_class_owner;
+ALTER TABLE classes ADD CONSTRAINT fk_class_owner FOREIGN KEY (owner_id) REFERENCES teachers(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_classes_owner_id ON classes(owner_id);
+
+-- Sample Data for Development
+-- Demo teacher (password: classswift-demo) owns the sample classes
+INSERT INTO teachers (email, name, password_hash) VALUES
+('demo@classswift.io', 'Demo Teacher', '$2a$10$9mr5NbZXlefvMx5Mx8.i0u7DbvvkR7ApezaPeJR9r8XKAe/OiCPwO')
+ON CONFLICT (email) DO NOTHING;
+
+UPDATE classes SET owner_id = (SELECT id FROM teachers WHERE email = 'demo@classswift.io')
+WHERE owner_id IS NULL AND id IN ('class-1', 'class-2', 'class-3', 'class-4', 'class-5');
@@ -0,0 +1,30 @@
+-- Student groups for ClassSwift Teacher Dashboard
+-- Groups are formed per class session; regrouping a session replaces its groups.
+
+CREATE TABLE IF NOT EXISTS student_groups (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL,                  -- Reference to class session
+    class_id VARCHAR(255) NOT NULL,               -- Reference to class
+    name VARCHAR(255) NOT NULL,                   -- Display name, e.g. "Group 1"
+    position INTEGER NOT NULL,                    -- Order of the group within the session
+    strategy VARCHAR(50) NOT NULL,                -- Strategy the group was formed with
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_student_group_session FOREIGN KEY (session_id) REFERENCES class_sessions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_student_group_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT unique_student_group_position UNIQUE (session_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS student_group_members (
+    id SERIAL PRIMARY KEY,
+    group_id INTEGER NOT NULL,                    -- Reference to student group
+    student_id INTEGER,                           -- Enrolled student (NULL for guests)
+    name VARCHAR(255) NOT NULL,                   -- Name at the time the group was formed
+    seat_number INTEGER NOT NULL DEFAULT 0,       -- Seat at the time the group was formed
+
+    CONSTRAINT fk_group_member_group FOREIGN KEY (group_id) REFERENCES student_groups(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_member_student FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_groups_session_id ON student_groups(session_id);
+CREATE INDEX IF NOT EXISTS idx_group_members_group_id ON student_group_members(group_id);
@@ -0,0 +1,20 @@
+-- Pairing constraints for ClassSwift Teacher Dashboard
+-- Keep-apart and keep-together rules between two students of a class, honored when forming groups.
+
+CREATE TABLE IF NOT EXISTS pairing_constraints (
+    id SERIAL PRIMARY KEY,
+    class_id VARCHAR(255) NOT NULL,               -- Reference to class
+    kind VARCHAR(20) NOT NULL,                    -- 'apart' or 'together'
+    student_a_id INTEGER NOT NULL,                -- Lower student ID of the pair
+    student_b_id INTEGER NOT NULL,                -- Higher student ID of the pair
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_pairing_constraint_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_pairing_constraint_student_a FOREIGN KEY (student_a_id) REFERENCES students(id) ON DELETE CASCADE,
+    CONSTRAINT fk_pairing_constraint_student_b FOREIGN KEY (student_b_id) REFERENCES students(id) ON DELETE CASCADE,
+    CONSTRAINT unique_pairing_constraint_pair UNIQUE (class_id, student_a_id, student_b_id),
+    CONSTRAINT chk_pairing_constraint_kind CHECK (kind IN ('apart', 'together')),
+    CONSTRAINT chk_pairing_constraint_order CHECK (student_a_id < student_b_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pairing_constraints_class_id ON pairing_constraints(class_id);
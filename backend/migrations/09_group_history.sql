@@ -0,0 +1,6 @@
+-- Group history for ClassSwift Teacher Dashboard
+-- Each session keeps the last groups formed for it, so a class's sessions form its grouping history.
+-- These indexes serve the pair co-occurrence queries behind fresh-mix grouping.
+
+CREATE INDEX IF NOT EXISTS idx_student_groups_class_id ON student_groups(class_id);
+CREATE INDEX IF NOT EXISTS idx_group_members_group_student ON student_group_members(group_id, student_id) WHERE student_id IS NOT NULL;
@@ -0,0 +1,23 @@
+-- Seating layouts for ClassSwift Teacher Dashboard
+-- A class's seating chart: a grid numbered row by row from 1, with disabled cells and named zones.
+-- Classes without a layout use a 5-column grid with one seat per unit of capacity.
+
+CREATE TABLE IF NOT EXISTS seating_layouts (
+    class_id VARCHAR(255) PRIMARY KEY,            -- Reference to class
+    rows INTEGER NOT NULL,                        -- Grid rows
+    columns INTEGER NOT NULL,                     -- Grid columns
+    disabled_seats JSONB NOT NULL DEFAULT '[]',   -- Cell numbers that hold no seat
+    zones JSONB NOT NULL DEFAULT '[]',            -- Named zones: [{"name": ..., "seats": [...]}]
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_seating_layout_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT chk_seating_layout_rows CHECK (rows BETWEEN 1 AND 50),
+    CONSTRAINT chk_seating_layout_columns CHECK (columns BETWEEN 1 AND 50)
+);
+
+DROP TRIGGER IF EXISTS trigger_seating_layouts_updated_at ON seating_layouts;
+CREATE TRIGGER trigger_seating_layouts_updated_at
+    BEFORE UPDATE ON seating_layouts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
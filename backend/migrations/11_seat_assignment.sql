@@ -0,0 +1,12 @@
+-- Seat assignment on join for ClassSwift Teacher Dashboard
+-- Guests, and students whose preferred seat is taken, are given a free seat chosen by the layout's seat policy.
+
+ALTER TABLE seating_layouts
+    ADD COLUMN IF NOT EXISTS seat_policy VARCHAR(20) NOT NULL DEFAULT 'next_free';  -- next_free, front_first or fill_by_zone
+
+ALTER TABLE seating_layouts DROP CONSTRAINT IF EXISTS chk_seating_layout_seat_policy;
+ALTER TABLE seating_layouts
+    ADD CONSTRAINT chk_seating_layout_seat_policy CHECK (seat_policy IN ('next_free', 'front_first', 'fill_by_zone'));
+
+-- Nobody shares a seat within a session (0 means no seat)
+CREATE UNIQUE INDEX IF NOT EXISTS unique_attendance_seat_per_session ON attendance_records(session_id, seat_number) WHERE seat_number > 0;
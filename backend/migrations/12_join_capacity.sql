@@ -0,0 +1,24 @@
+-- Join capacity for ClassSwift Teacher Dashboard
+-- Joins to a session are limited to the class capacity. Classes can wait-list joiners beyond it.
+
+ALTER TABLE classes ADD COLUMN IF NOT EXISTS waitlist_when_full BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS join_waitlist (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL,                  -- Reference to class session
+    class_id VARCHAR(255) NOT NULL,               -- Reference to class
+    student_id INTEGER,                           -- Enrolled student (NULL for guests)
+    name VARCHAR(255) NOT NULL,                   -- Name entered when joining
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_waitlist_session FOREIGN KEY (session_id) REFERENCES class_sessions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_waitlist_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_waitlist_student FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
+    CONSTRAINT chk_waitlist_name_not_empty CHECK (LENGTH(TRIM(name)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_session_id ON join_waitlist(session_id, id);
+
+-- A student, or a guest name, waits at most once per session
+CREATE UNIQUE INDEX IF NOT EXISTS unique_waitlist_student_per_session ON join_waitlist(session_id, student_id) WHERE student_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_waitlist_guest_per_session ON join_waitlist(session_id, LOWER(name)) WHERE student_id IS NULL;
@@ -0,0 +1,27 @@
+-- Join approval for ClassSwift Teacher Dashboard
+-- Classes can require the teacher to approve each join; joins wait in join_requests until decided.
+
+ALTER TABLE classes ADD COLUMN IF NOT EXISTS require_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS join_requests (
+    id SERIAL PRIMARY KEY,
+    class_id VARCHAR(255) NOT NULL,               -- Reference to class
+    student_id INTEGER,                           -- Enrolled student matching the name (NULL for guests)
+    name VARCHAR(255) NOT NULL,                   -- Name entered when joining
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    poll_token VARCHAR(64) NOT NULL,              -- Secret the joining device follows the request with
+    session_token TEXT,                           -- Student session token issued on approval
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP,                         -- When the teacher approved or rejected the request
+
+    CONSTRAINT fk_join_request_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_join_request_student FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE SET NULL,
+    CONSTRAINT chk_join_request_status CHECK (status IN ('pending', 'approved', 'rejected')),
+    CONSTRAINT chk_join_request_name_not_empty CHECK (LENGTH(TRIM(name)) > 0),
+    CONSTRAINT unique_join_request_poll_token UNIQUE (poll_token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_join_requests_class_status ON join_requests(class_id, status);
+
+-- A name waits at most once per class
+CREATE UNIQUE INDEX IF NOT EXISTS unique_join_request_pending_name ON join_requests(class_id, LOWER(name)) WHERE status = 'pending';
@@ -0,0 +1,10 @@
+-- Join locking for ClassSwift Teacher Dashboard
+-- Teachers can lock joining to the current session, and classes can lock it a set time after a session starts.
+
+ALTER TABLE classes ADD COLUMN IF NOT EXISTS auto_lock_minutes INTEGER NOT NULL DEFAULT 0;  -- 0 = never lock on its own
+
+ALTER TABLE classes DROP CONSTRAINT IF EXISTS chk_auto_lock_minutes_non_negative;
+ALTER TABLE classes ADD CONSTRAINT chk_auto_lock_minutes_non_negative CHECK (auto_lock_minutes >= 0);
+
+ALTER TABLE class_sessions ADD COLUMN IF NOT EXISTS joins_locked BOOLEAN NOT NULL DEFAULT FALSE;  -- Locked by the teacher
+ALTER TABLE class_sessions ADD COLUMN IF NOT EXISTS joins_lock_at TIMESTAMP;                     -- When joins lock on their own
@@ -0,0 +1,15 @@
+-- Student name matching for ClassSwift Teacher Dashboard
+-- Joins match names on name_key: the name trimmed, with single spaces, NFKC-normalized and case-folded.
+-- The service sets it whenever a student is created or renamed.
+
+ALTER TABLE students ADD COLUMN IF NOT EXISTS name_key TEXT;
+
+-- LOWER() agrees with the service's case folding for all but a few letters (such as ß);
+-- those names get their exact key the next time they are renamed
+UPDATE students
+SET name_key = LOWER(NORMALIZE(REGEXP_REPLACE(BTRIM(name), '\s+', ' ', 'g'), NFKC))
+WHERE name_key IS NULL;
+
+ALTER TABLE students ALTER COLUMN name_key SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_students_name_key ON students(name_key);
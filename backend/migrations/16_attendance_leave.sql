@@ -0,0 +1,26 @@
+-- Leaving a session for ClassSwift Teacher Dashboard
+-- Students and guests who leave, or are removed, keep their attendance record and points;
+-- the record is marked as left, which frees their seat for the rest of the session.
+
+ALTER TABLE attendance_records ADD COLUMN IF NOT EXISTS left_at TIMESTAMP;                           -- When they left (NULL while in the session)
+ALTER TABLE attendance_records ADD COLUMN IF NOT EXISTS left_reason VARCHAR(20) NOT NULL DEFAULT '';  -- left or removed
+
+ALTER TABLE attendance_records DROP CONSTRAINT IF EXISTS chk_attendance_left_reason;
+ALTER TABLE attendance_records
+    ADD CONSTRAINT chk_attendance_left_reason CHECK (left_reason IN ('', 'left', 'removed'));
+
+-- Only those still in the session hold a seat or their name; joining again after leaving adds a new record
+DROP INDEX IF EXISTS unique_attendance_student_per_session;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_attendance_student_per_session ON attendance_records(session_id, student_id) WHERE student_id IS NOT NULL AND left_at IS NULL;
+DROP INDEX IF EXISTS unique_attendance_guest_per_session;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_attendance_guest_per_session ON attendance_records(session_id, LOWER(name)) WHERE student_id IS NULL AND left_at IS NULL;
+DROP INDEX IF EXISTS unique_attendance_seat_per_session;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_attendance_seat_per_session ON attendance_records(session_id, seat_number) WHERE seat_number > 0 AND left_at IS NULL;
+
+-- Guest ledger entries are keyed by seat; when a guest leaves, theirs are tied to their attendance record
+-- so they do not pass to the next guest seated there
+ALTER TABLE point_events ADD COLUMN IF NOT EXISTS attendance_id INTEGER;
+
+ALTER TABLE point_events DROP CONSTRAINT IF EXISTS fk_point_event_attendance;
+ALTER TABLE point_events
+    ADD CONSTRAINT fk_point_event_attendance FOREIGN KEY (attendance_id) REFERENCES attendance_records(id) ON DELETE CASCADE;
@@ -0,0 +1,29 @@
+-- Guest name matching for ClassSwift Teacher Dashboard
+-- Guests are told apart by name_key, the same normalized form joins match students on (see 15_student_name_key.sql),
+-- so "Ｊames" and "james " are the same guest. The service sets it whenever a record is created or renamed.
+
+ALTER TABLE attendance_records ADD COLUMN IF NOT EXISTS name_key TEXT;
+ALTER TABLE join_waitlist ADD COLUMN IF NOT EXISTS name_key TEXT;
+ALTER TABLE join_requests ADD COLUMN IF NOT EXISTS name_key TEXT;
+
+UPDATE attendance_records
+SET name_key = LOWER(NORMALIZE(REGEXP_REPLACE(BTRIM(name), '\s+', ' ', 'g'), NFKC))
+WHERE name_key IS NULL;
+UPDATE join_waitlist
+SET name_key = LOWER(NORMALIZE(REGEXP_REPLACE(BTRIM(name), '\s+', ' ', 'g'), NFKC))
+WHERE name_key IS NULL;
+UPDATE join_requests
+SET name_key = LOWER(NORMALIZE(REGEXP_REPLACE(BTRIM(name), '\s+', ' ', 'g'), NFKC))
+WHERE name_key IS NULL;
+
+ALTER TABLE attendance_records ALTER COLUMN name_key SET NOT NULL;
+ALTER TABLE join_waitlist ALTER COLUMN name_key SET NOT NULL;
+ALTER TABLE join_requests ALTER COLUMN name_key SET NOT NULL;
+
+-- A guest name is in a session, or waiting for it, at most once; a name waits for approval at most once per class
+DROP INDEX IF EXISTS unique_attendance_guest_per_session;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_attendance_guest_per_session ON attendance_records(session_id, name_key) WHERE student_id IS NULL AND left_at IS NULL;
+DROP INDEX IF EXISTS unique_waitlist_guest_per_session;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_waitlist_guest_per_session ON join_waitlist(session_id, name_key) WHERE student_id IS NULL;
+DROP INDEX IF EXISTS unique_join_request_pending_name;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_join_request_pending_name ON join_requests(class_id, name_key) WHERE status = 'pending';
@@ -0,0 +1,18 @@
+-- Student ownership for ClassSwift Teacher Dashboard
+-- A student belongs to the teacher who created, imported or promoted them; only that teacher may rename,
+-- delete or enroll them, even before they are enrolled anywhere.
+
+ALTER TABLE students ADD COLUMN IF NOT EXISTS owner_id INTEGER;
+ALTER TABLE students DROP CONSTRAINT IF EXISTS fk_student_owner;
+ALTER TABLE students ADD CONSTRAINT fk_student_owner FOREIGN KEY (owner_id) REFERENCES teachers(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_students_owner_id ON students(owner_id);
+
+-- Existing students go to the teacher who owns every class they are enrolled in, if there is one
+UPDATE students s SET owner_id = owners.owner_id
+FROM (
+    SELECT sps.student_id, MIN(c.owner_id) AS owner_id
+    FROM student_preferred_seats sps JOIN classes c ON c.id = sps.class_id
+    GROUP BY sps.student_id
+    HAVING COUNT(DISTINCT c.owner_id) = 1 AND COUNT(c.owner_id) = COUNT(*)
+) owners
+WHERE s.id = owners.student_id AND s.owner_id IS NULL;
@@ -0,0 +1,137 @@
+// Package jsonschema generates JSON Schema (draft 2020-12) documents from Go types,
+// following the same field names and optionality rules as encoding/json.
+//
+// Named struct types become entries in "$defs" and are referenced with "$ref".
+// A field is required unless it is tagged omitempty; a pointer field that is not
+// omitempty may also be null.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema object.
+type Schema map[string]interface{}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Generator accumulates the "$defs" of the types it has described.
+type Generator struct {
+	defs Schema
+}
+
+// NewGenerator creates a generator with no definitions.
+func NewGenerator() *Generator {
+	return &Generator{defs: Schema{}}
+}
+
+// Defs returns the definitions of every named struct type described so far.
+func (g *Generator) Defs() Schema {
+	return g.defs
+}
+
+// Ref describes the type of v and returns a reference to its definition,
+// or an inline schema if it is not a named struct type.
+func (g *Generator) Ref(v interface{}) Schema {
+	return g.schemaFor(reflect.TypeOf(v))
+}
+
+func (g *Generator) schemaFor(t reflect.Type) Schema {
+	switch {
+	case t == nil:
+		return Schema{}
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			g.defs[t.Name()] = Schema{}
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return Schema{"$ref": "#/$defs/" + t.Name()}
+	default:
+		// interface{} and anything else JSON can hold
+		return Schema{}
+	}
+}
+
+func (g *Generator) structSchema(t reflect.Type) Schema {
+	properties := Schema{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		schema := g.schemaFor(field.Type)
+		if field.Type.Kind() == reflect.Pointer && !omitEmpty {
+			schema = Schema{"anyOf": []Schema{schema, {"type": "null"}}}
+		}
+		properties[name] = schema
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := Schema{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// jsonField returns the encoded name of a struct field and whether it is omitempty or skipped.
+func jsonField(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
@@ -0,0 +1,84 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+type testRecord struct {
+	ID       uint              `json:"id"`
+	Label    string            `json:"label,omitempty"`
+	Parent   *testItem         `json:"parent"`
+	Optional *int              `json:"optional,omitempty"`
+	Items    []testItem        `json:"items"`
+	Tags     map[string]string `json:"tags"`
+	At       time.Time         `json:"at"`
+	Any      interface{}       `json:"any"`
+	Skipped  string            `json:"-"`
+	hidden   string
+}
+
+func TestGenerator_Struct(t *testing.T) {
+	generator := NewGenerator()
+	ref := generator.Ref(testRecord{})
+	if ref["$ref"] != "#/$defs/testRecord" {
+		t.Fatalf("Expected reference to testRecord, got %v", ref)
+	}
+
+	defs := generator.Defs()
+	record := defs["testRecord"].(Schema)
+	if record["additionalProperties"] != false {
+		t.Error("Expected struct schemas to reject additional properties")
+	}
+	required := record["required"].([]string)
+	want := []string{"id", "parent", "items", "tags", "at", "any"}
+	if !reflect.DeepEqual(required, want) {
+		t.Errorf("Expected required %v, got %v", want, required)
+	}
+
+	properties := record["properties"].(Schema)
+	if _, ok := properties["Skipped"]; ok {
+		t.Error("Expected json:\"-\" fields to be skipped")
+	}
+	if _, ok := properties["hidden"]; ok {
+		t.Error("Expected unexported fields to be skipped")
+	}
+	if properties["id"].(Schema)["minimum"] != 0 {
+		t.Error("Expected unsigned integers to have a minimum of 0")
+	}
+	if properties["at"].(Schema)["format"] != "date-time" {
+		t.Error("Expected time.Time to be a date-time string")
+	}
+	if _, ok := properties["parent"].(Schema)["anyOf"]; !ok {
+		t.Error("Expected a non-omitempty pointer to be nullable")
+	}
+	if properties["optional"].(Schema)["type"] != "integer" {
+		t.Error("Expected an omitempty pointer to use its element schema")
+	}
+	if properties["items"].(Schema)["items"].(Schema)["$ref"] != "#/$defs/testItem" {
+		t.Error("Expected slice items to reference their struct definition")
+	}
+	if _, ok := defs["testItem"]; !ok {
+		t.Error("Expected nested structs to be added to $defs")
+	}
+}
+
+type testNode struct {
+	Children []testNode `json:"children"`
+}
+
+func TestGenerator_RecursiveType(t *testing.T) {
+	generator := NewGenerator()
+	generator.Ref(testNode{})
+
+	node := generator.Defs()["testNode"].(Schema)
+	children := node["properties"].(Schema)["children"].(Schema)
+	if children["items"].(Schema)["$ref"] != "#/$defs/testNode" {
+		t.Errorf("Expected recursive reference, got %v", children)
+	}
+}
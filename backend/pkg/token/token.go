@@ -0,0 +1,73 @@
+// Package token signs and verifies compact HMAC-SHA256 tokens carrying JSON claims.
+//
+// A token is "<base64url(claims JSON)>.<base64url(HMAC-SHA256 of the first part)>".
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformed is returned when a token is not in the expected format.
+	ErrMalformed = errors.New("token is malformed")
+	// ErrInvalidSignature is returned when a token was not signed with the expected secret.
+	ErrInvalidSignature = errors.New("token signature is invalid")
+	// ErrExpired is returned when a token's expiry has passed.
+	ErrExpired = errors.New("token has expired")
+)
+
+// Claims is implemented by token payloads that carry an expiry time.
+type Claims interface {
+	Expiry() time.Time
+}
+
+// Sign encodes claims as JSON and signs them with secret.
+func Sign(secret []byte, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signature(secret, encoded)), nil
+}
+
+// Verify checks the token signature with secret, decodes it into claims and rejects it if
+// it has expired at now.
+func Verify(secret []byte, tok string, claims Claims, now time.Time) error {
+	encoded, sig, ok := strings.Cut(tok, ".")
+	if !ok || encoded == "" || sig == "" {
+		return ErrMalformed
+	}
+
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return ErrMalformed
+	}
+	if !hmac.Equal(gotSig, signature(secret, encoded)) {
+		return ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ErrMalformed
+	}
+	if !now.Before(claims.Expiry()) {
+		return ErrExpired
+	}
+	return nil
+}
+
+func signature(secret []byte, encoded string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
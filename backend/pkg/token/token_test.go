@@ -0,0 +1,63 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testClaims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+func (c *testClaims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Now()
+	tok, err := Sign(secret, &testClaims{Subject: "student-1", ExpiresAt: now.Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var claims testClaims
+	if err := Verify(secret, tok, &claims, now); err != nil {
+		t.Fatalf("expected token to verify, got %v", err)
+	}
+	if claims.Subject != "student-1" {
+		t.Errorf("expected subject student-1, got %q", claims.Subject)
+	}
+}
+
+func TestVerify_Rejections(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Now()
+	tok, _ := Sign(secret, &testClaims{Subject: "student-1", ExpiresAt: now.Add(time.Hour).Unix()})
+	payload, sig, _ := strings.Cut(tok, ".")
+	forged, _ := Sign(secret, &testClaims{Subject: "student-2", ExpiresAt: now.Add(time.Hour).Unix()})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	cases := []struct {
+		name string
+		tok  string
+		key  []byte
+		at   time.Time
+		want error
+	}{
+		{"missing signature", payload, secret, now, ErrMalformed},
+		{"wrong secret", tok, []byte("other-secret"), now, ErrInvalidSignature},
+		{"tampered payload", forgedPayload + "." + sig, secret, now, ErrInvalidSignature},
+		{"expired", tok, secret, now.Add(2 * time.Hour), ErrExpired},
+	}
+
+	for _, tc := range cases {
+		var claims testClaims
+		if err := Verify(tc.key, tc.tok, &claims, tc.at); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"sync"
+
+	"classswift-backend/internal/model"
+)
+
+// Broadcaster fans class messages out to the WebSocket hubs of every backend replica.
+// Messages passed to Publish are handed to the deliver callback of every subscribed replica,
+// including the one that published them, stamped with the class's next sequence number.
+type Broadcaster interface {
+	// Publish assigns the message its class sequence number and sends it to every subscribed replica.
+	Publish(message model.WebSocketMessage) error
+	// Subscribe starts delivering published messages to deliver.
+	Subscribe(deliver func(model.WebSocketMessage)) error
+	// Close stops delivery and releases any connections.
+	Close() error
+}
+
+// MemoryBroadcaster delivers messages within the current process only.
+// It is the default for single-replica deployments.
+type MemoryBroadcaster struct {
+	mutex   sync.Mutex
+	deliver func(model.WebSocketMessage)
+	seqs    map[string]uint64
+}
+
+// NewMemoryBroadcaster creates an in-process broadcaster.
+func NewMemoryBroadcaster() *MemoryBroadcaster {
+	return &MemoryBroadcaster{seqs: make(map[string]uint64)}
+}
+
+// Publish stamps the message with the class's next sequence number and hands it straight to the subscriber, if any.
+// The lock is held while delivering so subscribers see each class's messages in sequence order.
+func (b *MemoryBroadcaster) Publish(message model.WebSocketMessage) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.seqs[message.ClassID]++
+	message.Seq = b.seqs[message.ClassID]
+	if b.deliver != nil {
+		b.deliver(message)
+	}
+	return nil
+}
+
+// Subscribe sets the callback that receives published messages.
+func (b *MemoryBroadcaster) Subscribe(deliver func(model.WebSocketMessage)) error {
+	b.mutex.Lock()
+	b.deliver = deliver
+	b.mutex.Unlock()
+	return nil
+}
+
+// Close removes the subscriber.
+func (b *MemoryBroadcaster) Close() error {
+	return b.Subscribe(nil)
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"classswift-backend/internal/model"
+)
+
+// collect returns a deliver callback that forwards messages to a channel.
+func collect() (func(model.WebSocketMessage), chan model.WebSocketMessage) {
+	received := make(chan model.WebSocketMessage, 16)
+	return func(message model.WebSocketMessage) { received <- message }, received
+}
+
+func expectMessage(t *testing.T, received chan model.WebSocketMessage, classID string, messageType string) {
+	t.Helper()
+	select {
+	case message := <-received:
+		if message.ClassID != classID || message.Type != messageType {
+			t.Errorf("Expected %s for class %s, got %s for class %s", messageType, classID, message.Type, message.ClassID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for %s for class %s", messageType, classID)
+	}
+}
+
+func TestMemoryBroadcaster(t *testing.T) {
+	broadcaster := NewMemoryBroadcaster()
+
+	// Publishing without a subscriber is a no-op
+	if err := broadcaster.Publish(model.WebSocketMessage{Type: "test", ClassID: "class-1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	deliver, received := collect()
+	broadcaster.Subscribe(deliver)
+	broadcaster.Publish(model.WebSocketMessage{Type: "test", ClassID: "class-1"})
+	expectMessage(t, received, "class-1", "test")
+
+	broadcaster.Close()
+	broadcaster.Publish(model.WebSocketMessage{Type: "test", ClassID: "class-1"})
+	if len(received) != 0 {
+		t.Error("Expected no delivery after Close")
+	}
+}
+
+func TestMemoryBroadcaster_SequencesPerClass(t *testing.T) {
+	broadcaster := NewMemoryBroadcaster()
+	deliver, received := collect()
+	broadcaster.Subscribe(deliver)
+
+	for _, classID := range []string{"class-1", "class-1", "class-2", "class-1"} {
+		broadcaster.Publish(model.WebSocketMessage{Type: "test", ClassID: classID})
+	}
+
+	for _, want := range []uint64{1, 2, 1, 3} {
+		if message := <-received; message.Seq != want {
+			t.Errorf("Expected seq %d for class %s, got %d", want, message.ClassID, message.Seq)
+		}
+	}
+}
+
+func TestRedisBroadcaster_FansOutAcrossReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	replicaA, err := NewRedisBroadcaster("redis://" + server.Addr())
+	if err != nil {
+		t.Fatalf("Failed to create broadcaster: %v", err)
+	}
+	defer replicaA.Close()
+	replicaB, err := NewRedisBroadcaster("redis://" + server.Addr())
+	if err != nil {
+		t.Fatalf("Failed to create broadcaster: %v", err)
+	}
+	defer replicaB.Close()
+
+	deliverA, receivedA := collect()
+	deliverB, receivedB := collect()
+	if err := replicaA.Subscribe(deliverA); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if err := replicaB.Subscribe(deliverB); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	message := model.WebSocketMessage{
+		Type:      "class_updated",
+		ClassID:   "X58E9647",
+		Data:      map[string]interface{}{"joiningStudent": map[string]interface{}{"name": "Philip"}},
+		Timestamp: time.Now(),
+	}
+	if err := replicaA.Publish(message); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	expectMessage(t, receivedA, "X58E9647", "class_updated")
+	expectMessage(t, receivedB, "X58E9647", "class_updated")
+
+	// Both replicas share the class sequence kept in Redis
+	replicaB.Publish(message)
+	for _, received := range []chan model.WebSocketMessage{receivedA, receivedB} {
+		select {
+		case got := <-received:
+			if got.Seq != 2 {
+				t.Errorf("Expected seq 2 for the second message, got %d", got.Seq)
+			}
+			if got.Type != "class_updated" {
+				t.Errorf("Expected class_updated, got %s", got.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for second message")
+		}
+	}
+}
+
+func TestNewRedisBroadcaster_Errors(t *testing.T) {
+	if _, err := NewRedisBroadcaster("not a url"); err == nil {
+		t.Error("Expected error for invalid Redis URL")
+	}
+
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+	if _, err := NewRedisBroadcaster("redis://" + addr); err == nil {
+		t.Error("Expected error when Redis is unreachable")
+	}
+}
+
+func TestWebSocketManager_BroadcastThroughBroadcaster(t *testing.T) {
+	server := miniredis.RunT(t)
+	broadcaster, err := NewRedisBroadcaster("redis://" + server.Addr())
+	if err != nil {
+		t.Fatalf("Failed to create broadcaster: %v", err)
+	}
+	defer broadcaster.Close()
+
+	// The hub is not started, so delivered messages stay queued on its broadcast channel
+	manager := NewWebSocketManagerWithBroadcaster(broadcaster)
+	if err := broadcaster.Subscribe(manager.deliver); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	manager.Broadcast(model.WebSocketMessage{Type: "points_updated", ClassID: "class-1"})
+	expectMessage(t, manager.GetHub().Broadcast, "class-1", "points_updated")
+}
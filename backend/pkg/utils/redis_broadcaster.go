@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"classswift-backend/internal/model"
+	"classswift-backend/pkg/logger"
+)
+
+// redisClassChannelPrefix is prepended to the class ID to form the pub/sub channel for a class.
+const redisClassChannelPrefix = "classswift:class:"
+
+// redisSeqKeySuffix is appended to the class channel to form the key holding the class's last sequence number.
+const redisSeqKeySuffix = ":seq"
+
+// redisPublishTimeout bounds how long a publish may wait on Redis.
+const redisPublishTimeout = 2 * time.Second
+
+// redisPublishScript increments the class sequence number and publishes the message with it in one atomic step,
+// so every replica receives a class's messages in sequence order. ARGV[1] is the JSON-encoded message without a seq field.
+var redisPublishScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+redis.call('PUBLISH', KEYS[2], '{"seq":' .. seq .. ',' .. string.sub(ARGV[1], 2))
+return seq
+`)
+
+// RedisBroadcaster fans messages out across replicas through Redis pub/sub.
+// Each class is published on its own channel; every replica pattern-subscribes to all class
+// channels and delivers what it receives to its local hub.
+type RedisBroadcaster struct {
+	client *redis.Client
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+}
+
+// NewRedisBroadcaster connects to the Redis server at redisURL (e.g. "redis://redis:6379").
+func NewRedisBroadcaster(redisURL string) (*RedisBroadcaster, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	return NewRedisBroadcasterWithClient(redis.NewClient(opts))
+}
+
+// NewRedisBroadcasterWithClient creates a broadcaster using an existing Redis client.
+func NewRedisBroadcasterWithClient(client *redis.Client) (*RedisBroadcaster, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisPublishTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return &RedisBroadcaster{client: client}, nil
+}
+
+// Publish sends the message on its class channel, stamped with the class sequence number kept in Redis.
+func (b *RedisBroadcaster) Publish(message model.WebSocketMessage) error {
+	message.Seq = 0
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisPublishTimeout)
+	defer cancel()
+	channel := redisClassChannelPrefix + message.ClassID
+	return redisPublishScript.Run(ctx, b.client, []string{channel + redisSeqKeySuffix, channel}, payload).Err()
+}
+
+// Subscribe subscribes to every class channel and delivers received messages until Close is called.
+// It returns once Redis has confirmed the subscription.
+func (b *RedisBroadcaster) Subscribe(deliver func(model.WebSocketMessage)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	pubsub := b.client.PSubscribe(ctx, redisClassChannelPrefix+"*")
+	if _, err := pubsub.Receive(ctx); err != nil {
+		cancel()
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to Redis class channels: %w", err)
+	}
+	b.pubsub = pubsub
+	b.cancel = cancel
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			var message model.WebSocketMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				logger.Errorf("Error unmarshaling Redis message on %s: %v", msg.Channel, err)
+				continue
+			}
+			if message.ClassID == "" {
+				message.ClassID = strings.TrimPrefix(msg.Channel, redisClassChannelPrefix)
+			}
+			deliver(message)
+		}
+	}()
+	return nil
+}
+
+// Close stops the subscription and closes the Redis connection.
+func (b *RedisBroadcaster) Close() error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	if b.pubsub != nil {
+		b.pubsub.Close()
+	}
+	return b.client.Close()
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -11,17 +12,41 @@ import (
 	"classswift-backend/pkg/logger"
 )
 
+const (
+	// clientSendQueueSize is how many messages may wait for a client before it is evicted as a slow consumer
+	clientSendQueueSize = 256
+	// writeWait bounds how long a single write to a client may block
+	writeWait = 10 * time.Second
+	// pingPeriod is how often clients are pinged to keep the connection alive
+	pingPeriod = 30 * time.Second
+)
+
 // WebSocketManager manages WebSocket hub operations
 type WebSocketManager struct {
-	hub   *model.WebSocketHub
-	mutex sync.RWMutex
+	hub         *model.WebSocketHub
+	broadcaster Broadcaster
+	mutex       sync.RWMutex
+
+	// Recent messages per class, replayed to clients that reconnect with a sequence number
+	history map[string]*classHistory
+
+	// Slow-consumer counters
+	droppedMessages atomic.Uint64
+	evictedClients  atomic.Uint64
 }
 
-// NewWebSocketManager creates a new WebSocket manager
+// NewWebSocketManager creates a new WebSocket manager that broadcasts within this process only
 func NewWebSocketManager() *WebSocketManager {
+	return NewWebSocketManagerWithBroadcaster(NewMemoryBroadcaster())
+}
+
+// NewWebSocketManagerWithBroadcaster creates a new WebSocket manager that fans messages out through broadcaster
+func NewWebSocketManagerWithBroadcaster(broadcaster Broadcaster) *WebSocketManager {
 	return &WebSocketManager{
+		broadcaster: broadcaster,
+		history:     make(map[string]*classHistory),
 		hub: &model.WebSocketHub{
-			Clients:    make(map[string]map[*websocket.Conn]bool),
+			Clients:    make(map[string]map[*model.Client]bool),
 			Broadcast:  make(chan model.WebSocketMessage, 256),
 			Register:   make(chan *model.Client, 256),
 			Unregister: make(chan *model.Client, 256),
@@ -29,10 +54,16 @@ func NewWebSocketManager() *WebSocketManager {
 	}
 }
 
-// Start starts the WebSocket hub event loop
-func (w *WebSocketManager) Start() {
+// Start starts the WebSocket hub event loop and subscribes it to the broadcaster
+func (w *WebSocketManager) Start() error {
 	go w.run()
+	if w.broadcaster != nil {
+		if err := w.broadcaster.Subscribe(w.deliver); err != nil {
+			return err
+		}
+	}
 	logger.Info("WebSocket hub started")
+	return nil
 }
 
 // GetHub returns the WebSocket hub
@@ -58,79 +89,247 @@ func (w *WebSocketManager) UnregisterClient(client *model.Client) {
 	w.hub.Unregister <- client
 }
 
-// Broadcast sends a message to all clients in a class
+// Broadcast sends a message to all clients in a class, on every replica sharing the broadcaster
 func (w *WebSocketManager) Broadcast(message model.WebSocketMessage) {
 	if w.hub == nil {
 		logger.Error("WebSocket hub not initialized")
 		return
 	}
 
+	if w.broadcaster == nil {
+		w.deliver(message)
+		return
+	}
+	if err := w.broadcaster.Publish(message); err != nil {
+		logger.Errorf("Failed to publish %s event for class %s: %v", message.Type, message.ClassID, err)
+	}
+}
+
+// SendToClient queues a message for a single client on this replica, such as a reply to something it sent.
+// The message is not sequenced or kept for replay. It is dropped if the client is not registered or its queue is full.
+func (w *WebSocketManager) SendToClient(client *model.Client, message model.WebSocketMessage) {
+	messageData, err := json.Marshal(message)
+	if err != nil {
+		logger.Errorf("Error marshaling WebSocket message: %v", err)
+		return
+	}
+
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if !w.hub.Clients[client.ClassID][client] {
+		return
+	}
+	select {
+	case client.Send <- messageData:
+	default:
+		w.droppedMessages.Add(1)
+		logger.Errorf("Dropped %s reply to client in class %s: send queue full", message.Type, client.ClassID)
+	}
+}
+
+// deliver queues a published message for the clients connected to this replica
+func (w *WebSocketManager) deliver(message model.WebSocketMessage) {
 	select {
 	case w.hub.Broadcast <- message:
 		logger.Infof("Broadcasting %s event for class %s", message.Type, message.ClassID)
 	default:
+		w.droppedMessages.Add(1)
 		logger.Error("WebSocket broadcast channel is full")
 	}
 }
 
+// Stats returns the current connection count and slow-consumer counters
+func (w *WebSocketManager) Stats() model.WebSocketStats {
+	stats := model.WebSocketStats{
+		DroppedMessages: w.droppedMessages.Load(),
+		EvictedClients:  w.evictedClients.Load(),
+	}
+	if w.hub == nil {
+		return stats
+	}
+	w.mutex.RLock()
+	for _, clients := range w.hub.Clients {
+		stats.Connections += len(clients)
+	}
+	w.mutex.RUnlock()
+	return stats
+}
+
+// LatestSeq returns the sequence number of the last message delivered for a class, or 0 if none
+func (w *WebSocketManager) LatestSeq(classID string) uint64 {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if history := w.history[classID]; history != nil {
+		return history.latest()
+	}
+	return 0
+}
+
 // run starts the WebSocket hub event loop
+// The loop never writes to connections itself; each client's writePump does, so a stalled
+// client cannot hold up broadcasts to other clients or classes.
 func (w *WebSocketManager) run() {
 	h := w.hub
 	for {
 		select {
 		case client := <-h.Register:
-			w.mutex.Lock()
-			if client.Conn != nil {
-				if h.Clients[client.ClassID] == nil {
-					h.Clients[client.ClassID] = make(map[*websocket.Conn]bool)
-				}
-				h.Clients[client.ClassID][client.Conn] = true
-				logger.Infof("Client connected to class %s. Total connections: %d",
-					client.ClassID, len(h.Clients[client.ClassID]))
-			}
-			w.mutex.Unlock()
+			w.addClient(client)
 
 		case client := <-h.Unregister:
 			w.mutex.Lock()
-			if clients, ok := h.Clients[client.ClassID]; ok {
-				if _, ok := clients[client.Conn]; ok {
-					delete(clients, client.Conn)
-					if client.Conn != nil {
-						client.Conn.Close()
-					}
-					if len(clients) == 0 {
-						delete(h.Clients, client.ClassID)
-					}
-				}
-			}
+			removed := w.removeClient(client)
 			w.mutex.Unlock()
-			logger.Infof("Client disconnected from class %s", client.ClassID)
+			if removed {
+				logger.Infof("Client disconnected from class %s", client.ClassID)
+			}
 
 		case message := <-h.Broadcast:
-			w.mutex.RLock()
-			clients := h.Clients[message.ClassID]
-			w.mutex.RUnlock()
-
-			if clients != nil {
-				messageData, err := json.Marshal(message)
-				if err != nil {
-					logger.Errorf("Error marshaling WebSocket message: %v", err)
-					continue
-				}
-
-				for conn := range clients {
-					select {
-					case <-time.After(time.Second * 1):
-						// Connection write timeout
-						h.Unregister <- &model.Client{Conn: conn, ClassID: message.ClassID}
-					default:
-						if err := conn.WriteMessage(websocket.TextMessage, messageData); err != nil {
-							logger.Errorf("Error writing to WebSocket: %v", err)
-							h.Unregister <- &model.Client{Conn: conn, ClassID: message.ClassID}
-						}
-					}
-				}
+			w.fanOut(message)
+		}
+	}
+}
+
+// addClient adds a client to its class and starts its writer goroutine.
+// The client's initial message is queued first; a resuming client then has the messages it missed queued,
+// or a resync notice if they are gone.
+func (w *WebSocketManager) addClient(client *model.Client) {
+	if client.Conn == nil {
+		return
+	}
+	client.Send = make(chan []byte, clientSendQueueSize)
+	if client.Initial != nil {
+		if messageData, err := json.Marshal(client.Initial); err != nil {
+			logger.Errorf("Error marshaling WebSocket message: %v", err)
+		} else {
+			client.Send <- messageData
+		}
+	}
+
+	w.mutex.Lock()
+	if client.Resume {
+		w.queueReplay(client)
+	}
+	clients := w.hub.Clients[client.ClassID]
+	if clients == nil {
+		clients = make(map[*model.Client]bool)
+		w.hub.Clients[client.ClassID] = clients
+	}
+	clients[client] = true
+	w.mutex.Unlock()
+
+	go w.writePump(client)
+	logger.Infof("Client connected to class %s. Total connections: %d", client.ClassID, len(clients))
+}
+
+// queueReplay queues the class messages a resuming client missed. The caller must hold the write lock,
+// so no message can be fanned out between the replay and the client joining its class.
+func (w *WebSocketManager) queueReplay(client *model.Client) {
+	history := w.history[client.ClassID]
+	if history == nil {
+		history = &classHistory{}
+	}
+
+	missed, ok := history.since(client.Since)
+	if !ok {
+		logger.Infof("Client resuming class %s from seq %d must resync", client.ClassID, client.Since)
+		resync, err := json.Marshal(model.NewWebSocketMessage(client.ClassID, model.ResyncRequiredEvent{LatestSeq: history.latest()}))
+		if err != nil {
+			logger.Errorf("Error marshaling WebSocket message: %v", err)
+			return
+		}
+		client.Send <- resync
+		return
+	}
+
+	logger.Infof("Replaying %d messages to client resuming class %s from seq %d", len(missed), client.ClassID, client.Since)
+	for _, messageData := range missed {
+		client.Send <- messageData
+	}
+}
+
+// removeClient removes a client, closing its send queue and connection. The caller must hold the write lock.
+// It reports whether the client was still registered.
+func (w *WebSocketManager) removeClient(client *model.Client) bool {
+	clients, ok := w.hub.Clients[client.ClassID]
+	if !ok || !clients[client] {
+		return false
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(w.hub.Clients, client.ClassID)
+	}
+	close(client.Send)
+	client.Conn.Close()
+	return true
+}
+
+// fanOut records a message in its class history and queues it on the send queue of every client in the class.
+// Clients whose queue is full are evicted rather than allowed to block the hub.
+func (w *WebSocketManager) fanOut(message model.WebSocketMessage) {
+	messageData, err := json.Marshal(message)
+	if err != nil {
+		logger.Errorf("Error marshaling WebSocket message: %v", err)
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if message.Seq != 0 {
+		history := w.history[message.ClassID]
+		if history == nil {
+			history = &classHistory{}
+			w.history[message.ClassID] = history
+		}
+		history.add(message.Seq, messageData)
+	}
+
+	for client := range w.hub.Clients[message.ClassID] {
+		select {
+		case client.Send <- messageData:
+		default:
+			w.removeClient(client)
+			w.droppedMessages.Add(1)
+			w.evictedClients.Add(1)
+			logger.Errorf("Evicted slow WebSocket client from class %s: send queue full", client.ClassID)
+		}
+	}
+}
+
+// writePump writes queued messages and periodic pings to a client's connection until its send queue is closed
+func (w *WebSocketManager) writePump(client *model.Client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case messageData, ok := <-client.Send:
+			if !ok {
+				return
+			}
+			client.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := client.Conn.WriteMessage(websocket.TextMessage, messageData); err != nil {
+				logger.Errorf("Error writing to WebSocket: %v", err)
+				w.hub.Unregister <- client
+				w.drain(client)
+				return
+			}
+
+		case <-ticker.C:
+			client.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := client.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				logger.Errorf("Failed to send ping to client in class %s: %v", client.ClassID, err)
+				w.hub.Unregister <- client
+				w.drain(client)
+				return
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+
+// drain discards queued messages until the hub closes the client's send queue,
+// so the hub never blocks on a client whose writer has stopped
+func (w *WebSocketManager) drain(client *model.Client) {
+	for range client.Send {
+	}
+}
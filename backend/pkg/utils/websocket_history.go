@@ -0,0 +1,61 @@
+package utils
+
+// classHistorySize is how many recent messages are kept per class for replay to reconnecting clients.
+// It is kept below clientSendQueueSize so a full replay, after the client's initial message, fits in a new send queue.
+const classHistorySize = 200
+
+// historyEntry is a delivered class message kept for replay, already encoded for the wire
+type historyEntry struct {
+	seq  uint64
+	data []byte
+}
+
+// classHistory is a bounded buffer of a class's most recent messages in sequence order
+type classHistory struct {
+	entries []historyEntry
+	start   int
+}
+
+// add appends a message, overwriting the oldest once the buffer is full
+func (h *classHistory) add(seq uint64, data []byte) {
+	entry := historyEntry{seq: seq, data: data}
+	if len(h.entries) < classHistorySize {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	h.entries[h.start] = entry
+	h.start = (h.start + 1) % classHistorySize
+}
+
+// latest returns the sequence number of the newest buffered message, or 0 if the buffer is empty
+func (h *classHistory) latest() uint64 {
+	if len(h.entries) == 0 {
+		return 0
+	}
+	return h.entries[(h.start+len(h.entries)-1)%len(h.entries)].seq
+}
+
+// since returns the encoded messages published after seq, oldest first.
+// It reports false if any of them are no longer buffered, in which case the client must resync.
+func (h *classHistory) since(seq uint64) ([][]byte, bool) {
+	latest := h.latest()
+	if seq > latest {
+		// The client saw messages this buffer never did, e.g. from before a restart
+		return nil, false
+	}
+	if seq == latest {
+		return nil, true
+	}
+	if h.entries[h.start].seq > seq+1 {
+		return nil, false
+	}
+
+	var missed [][]byte
+	for i := 0; i < len(h.entries); i++ {
+		entry := h.entries[(h.start+i)%len(h.entries)]
+		if entry.seq > seq {
+			missed = append(missed, entry.data)
+		}
+	}
+	return missed, true
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClassHistory_Since(t *testing.T) {
+	history := &classHistory{}
+	if missed, ok := history.since(0); !ok || len(missed) != 0 {
+		t.Errorf("Expected nothing to replay from an empty history, got %d messages, ok=%t", len(missed), ok)
+	}
+
+	for seq := uint64(1); seq <= 5; seq++ {
+		history.add(seq, []byte(fmt.Sprint(seq)))
+	}
+
+	missed, ok := history.since(2)
+	if !ok || len(missed) != 3 || string(missed[0]) != "3" || string(missed[2]) != "5" {
+		t.Errorf("Expected messages 3-5, got %q, ok=%t", missed, ok)
+	}
+	if missed, ok := history.since(5); !ok || len(missed) != 0 {
+		t.Errorf("Expected nothing to replay when up to date, got %d messages, ok=%t", len(missed), ok)
+	}
+	if _, ok := history.since(9); ok {
+		t.Error("Expected resync when the client is ahead of the history")
+	}
+}
+
+func TestClassHistory_Bounded(t *testing.T) {
+	history := &classHistory{}
+	total := uint64(classHistorySize + 10)
+	for seq := uint64(1); seq <= total; seq++ {
+		history.add(seq, []byte(fmt.Sprint(seq)))
+	}
+
+	if len(history.entries) != classHistorySize {
+		t.Errorf("Expected history bounded to %d entries, got %d", classHistorySize, len(history.entries))
+	}
+	if history.latest() != total {
+		t.Errorf("Expected latest seq %d, got %d", total, history.latest())
+	}
+	if _, ok := history.since(5); ok {
+		t.Error("Expected resync when missed messages were overwritten")
+	}
+
+	missed, ok := history.since(total - classHistorySize)
+	if !ok || len(missed) != classHistorySize || string(missed[0]) != fmt.Sprint(total-classHistorySize+1) {
+		t.Errorf("Expected the whole buffer in order, got %d messages, ok=%t", len(missed), ok)
+	}
+}
@@ -0,0 +1,273 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"classswift-backend/internal/model"
+)
+
+// fakeConn is an in-memory model.Conn that counts written messages.
+// When block is set, writes stall until the connection is closed.
+type fakeConn struct {
+	written atomic.Int64
+	block   chan struct{}
+	once    sync.Once
+	closed  chan struct{}
+
+	// When record is set, the last written message is kept
+	record bool
+	mutex  sync.Mutex
+	last   []byte
+}
+
+func newFakeConn(stalled bool) *fakeConn {
+	conn := &fakeConn{closed: make(chan struct{})}
+	if stalled {
+		conn.block = make(chan struct{})
+	}
+	return conn
+}
+
+func (c *fakeConn) WriteMessage(messageType int, data []byte) error {
+	if c.block != nil {
+		select {
+		case <-c.block:
+		case <-c.closed:
+			return fmt.Errorf("connection closed")
+		}
+	}
+	if c.record {
+		c.mutex.Lock()
+		c.last = data
+		c.mutex.Unlock()
+	}
+	c.written.Add(1)
+	return nil
+}
+
+func (c *fakeConn) lastMessage() []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.last
+}
+
+func (c *fakeConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *fakeConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebSocketManager_WritePumpDelivers(t *testing.T) {
+	manager := NewWebSocketManager()
+	manager.Start()
+
+	conn := newFakeConn(false)
+	client := &model.Client{Conn: conn, ClassID: "class-1"}
+	manager.RegisterClient(client)
+	waitFor(t, func() bool { return manager.Stats().Connections == 1 })
+
+	for i := 0; i < 3; i++ {
+		manager.Broadcast(model.WebSocketMessage{Type: "test", ClassID: "class-1"})
+	}
+	waitFor(t, func() bool { return conn.written.Load() == 3 })
+
+	manager.UnregisterClient(client)
+	waitFor(t, func() bool { return manager.Stats().Connections == 0 })
+	select {
+	case <-conn.closed:
+	default:
+		t.Error("Expected connection to be closed on unregister")
+	}
+}
+
+func TestWebSocketManager_EvictsSlowConsumer(t *testing.T) {
+	manager := NewWebSocketManager()
+	manager.Start()
+
+	stalled := newFakeConn(true)
+	healthy := newFakeConn(false)
+	otherClass := newFakeConn(false)
+	manager.RegisterClient(&model.Client{Conn: stalled, ClassID: "class-1"})
+	manager.RegisterClient(&model.Client{Conn: healthy, ClassID: "class-1"})
+	manager.RegisterClient(&model.Client{Conn: otherClass, ClassID: "class-2"})
+	waitFor(t, func() bool { return manager.Stats().Connections == 3 })
+
+	// The stalled client holds one message in its writer and clientSendQueueSize in its queue
+	// Send in batches the healthy client can absorb so only the stalled one overflows
+	messages := clientSendQueueSize + 10
+	for i := 0; i < messages; i++ {
+		manager.fanOut(model.WebSocketMessage{Type: "test", ClassID: "class-1"})
+		if (i+1)%(clientSendQueueSize/2) == 0 {
+			sent := int64(i + 1)
+			waitFor(t, func() bool { return healthy.written.Load() == sent })
+		}
+	}
+	manager.Broadcast(model.WebSocketMessage{Type: "test", ClassID: "class-2"})
+
+	waitFor(t, func() bool { return healthy.written.Load() == int64(messages) })
+	waitFor(t, func() bool { return otherClass.written.Load() == 1 })
+
+	stats := manager.Stats()
+	if stats.Connections != 2 {
+		t.Errorf("Expected stalled client to be evicted leaving 2 connections, got %d", stats.Connections)
+	}
+	if stats.EvictedClients != 1 || stats.DroppedMessages != 1 {
+		t.Errorf("Expected 1 eviction and 1 dropped message, got %+v", stats)
+	}
+	select {
+	case <-stalled.closed:
+	default:
+		t.Error("Expected evicted connection to be closed")
+	}
+}
+
+func TestWebSocketManager_ReplaysMissedMessages(t *testing.T) {
+	manager := NewWebSocketManager()
+	manager.Start()
+
+	for i := 0; i < 5; i++ {
+		manager.Broadcast(model.WebSocketMessage{Type: "test", ClassID: "class-1"})
+	}
+	waitFor(t, func() bool {
+		manager.mutex.RLock()
+		defer manager.mutex.RUnlock()
+		return manager.history["class-1"] != nil && manager.history["class-1"].latest() == 5
+	})
+
+	resumed := newFakeConn(false)
+	manager.RegisterClient(&model.Client{Conn: resumed, ClassID: "class-1", Resume: true, Since: 3})
+	fresh := newFakeConn(false)
+	manager.RegisterClient(&model.Client{Conn: fresh, ClassID: "class-1"})
+	waitFor(t, func() bool { return manager.Stats().Connections == 2 })
+
+	manager.Broadcast(model.WebSocketMessage{Type: "test", ClassID: "class-1"})
+	waitFor(t, func() bool { return resumed.written.Load() == 3 })
+	waitFor(t, func() bool { return fresh.written.Load() == 1 })
+}
+
+func TestWebSocketManager_ResyncWhenHistoryMissing(t *testing.T) {
+	manager := NewWebSocketManager()
+	manager.Start()
+
+	conn := newFakeConn(false)
+	conn.record = true
+	manager.RegisterClient(&model.Client{Conn: conn, ClassID: "class-1", Resume: true, Since: 42})
+	waitFor(t, func() bool { return conn.written.Load() == 1 })
+
+	var message model.WebSocketMessage
+	if err := json.Unmarshal(conn.lastMessage(), &message); err != nil {
+		t.Fatalf("Failed to unmarshal message: %v", err)
+	}
+	if message.Type != model.EventResyncRequired || message.ClassID != "class-1" {
+		t.Errorf("Expected %s for class-1, got %s for %s", model.EventResyncRequired, message.Type, message.ClassID)
+	}
+}
+
+func TestWebSocketManager_InitialMessageFirst(t *testing.T) {
+	manager := NewWebSocketManager()
+	manager.Start()
+
+	for i := 0; i < 2; i++ {
+		manager.Broadcast(model.WebSocketMessage{Type: "test", ClassID: "class-1"})
+	}
+	waitFor(t, func() bool { return manager.LatestSeq("class-1") == 2 })
+
+	conn := newFakeConn(false)
+	conn.record = true
+	manager.RegisterClient(&model.Client{
+		Conn:    conn,
+		ClassID: "class-1",
+		Initial: &model.WebSocketMessage{Type: "class_state", ClassID: "class-1"},
+		Resume:  true,
+		Since:   1,
+	})
+	waitFor(t, func() bool { return conn.written.Load() == 2 })
+
+	var message model.WebSocketMessage
+	if err := json.Unmarshal(conn.lastMessage(), &message); err != nil {
+		t.Fatalf("Failed to unmarshal message: %v", err)
+	}
+	if message.Type != "test" || message.Seq != 2 {
+		t.Errorf("Expected replayed seq 2 after the initial message, got %s seq %d", message.Type, message.Seq)
+	}
+}
+
+func TestWebSocketManager_SendToClient(t *testing.T) {
+	manager := NewWebSocketManager()
+	manager.Start()
+
+	target := newFakeConn(false)
+	other := newFakeConn(false)
+	client := &model.Client{Conn: target, ClassID: "class-1"}
+	manager.RegisterClient(client)
+	manager.RegisterClient(&model.Client{Conn: other, ClassID: "class-1"})
+	waitFor(t, func() bool { return manager.Stats().Connections == 2 })
+
+	manager.SendToClient(client, model.NewWebSocketMessage("class-1", model.PongEvent{RequestID: "r1"}))
+	waitFor(t, func() bool { return target.written.Load() == 1 })
+	if other.written.Load() != 0 {
+		t.Error("Expected a reply to reach only its client")
+	}
+	if manager.LatestSeq("class-1") != 0 {
+		t.Error("Expected replies not to be kept for replay")
+	}
+
+	// Replies to clients that are gone are dropped
+	manager.UnregisterClient(client)
+	waitFor(t, func() bool { return manager.Stats().Connections == 1 })
+	manager.SendToClient(client, model.NewWebSocketMessage("class-1", model.PongEvent{RequestID: "r2"}))
+}
+
+// BenchmarkWebSocketManager_FanOut measures hub throughput broadcasting to one class with
+// thousands of connections, with and without a stalled connection among them.
+func BenchmarkWebSocketManager_FanOut(b *testing.B) {
+	for _, connections := range []int{1000, 5000} {
+		for _, withStalled := range []bool{false, true} {
+			name := fmt.Sprintf("conns=%d/stalled=%t", connections, withStalled)
+			b.Run(name, func(b *testing.B) {
+				manager := NewWebSocketManager()
+				manager.Start()
+
+				conns := make([]*fakeConn, connections)
+				for i := range conns {
+					conns[i] = newFakeConn(withStalled && i == 0)
+					manager.RegisterClient(&model.Client{Conn: conns[i], ClassID: "bench-class"})
+				}
+				for manager.Stats().Connections < connections {
+					time.Sleep(time.Millisecond)
+				}
+
+				message := model.WebSocketMessage{Type: "points_updated", ClassID: "bench-class", Data: map[string]int{"delta": 1}}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					manager.fanOut(message)
+				}
+				b.StopTimer()
+
+				for _, conn := range conns {
+					conn.Close()
+				}
+				b.ReportMetric(float64(manager.Stats().DroppedMessages), "dropped")
+			})
+		}
+	}
+}
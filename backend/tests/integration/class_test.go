@@ -6,7 +6,9 @@ import (
 	"classswift-backend/pkg/database"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 
@@ -36,6 +38,7 @@ func SetupTestServer() *gin.Engine {
 	r.GET("/classes/:classId", handler.GetClass)
 	r.GET("/classes/:classId/qr", handler.GetClassQRCode)
 	r.GET("/classes/:classId/join", handler.HandleStudentJoin)
+	r.POST("/classes/:classId/join", handler.SubmitStudentJoin)
 	return r
 }
 
@@ -75,7 +78,6 @@ func TestGetClassIntegration(t *testing.T) {
 	}
 }
 
-
 func TestGetClassQRCodeIntegration(t *testing.T) {
 	t.Skip("Integration test requires database connection")
 	r := SetupTestServer()
@@ -101,3 +103,18 @@ func TestHandleStudentJoinIntegration(t *testing.T) {
 		t.Fatalf("Expected 302 or 200, got %d", w.Code)
 	}
 }
+
+func TestSubmitStudentJoinIntegration(t *testing.T) {
+	t.Skip("Integration test requires database connection")
+	r := SetupTestServer()
+
+	form := url.Values{"name": {"Philip"}}
+	req, _ := http.NewRequest("POST", "/classes/X58E9647/join", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected 303 See Other, got %d", w.Code)
+	}
+}
@@ -70,21 +70,19 @@ const ClassMgmtModal: React.FC<ClassMgmtModalProps> = ({ onClose, classId }) =>
 
   // Handle websocket class updates
   useEffect(() => {
-    if (lastMessage && lastMessage.type === 'class_updated') {
-      console.log('🔄 WebSocket class_updated received:', lastMessage.data);
-      if (lastMessage.data.joiningStudent) {
-        const student = lastMessage.data.joiningStudent;
-        if (student && student.seatNumber !== undefined && student.seatNumber !== null && student.name) {
-          // Dispatch to the classes store to update seat map
-          dispatch(updateSeatFromWebSocket({
-            classId,
-            joiningStudent: {
-              name: student.name,
-              seatNumber: student.seatNumber,
-              id: student.id // Will be undefined for guest students
-            }
-          }));
-        }
+    if (lastMessage && lastMessage.type === 'student_joined') {
+      console.log('🔄 WebSocket student_joined received:', lastMessage.data);
+      const student = lastMessage.data;
+      if (student && student.seatNumber !== undefined && student.seatNumber !== null && student.name) {
+        // Dispatch to the classes store to update seat map
+        dispatch(updateSeatFromWebSocket({
+          classId,
+          joiningStudent: {
+            name: student.name,
+            seatNumber: student.seatNumber,
+            id: student.studentId // Will be undefined for guest students
+          }
+        }));
       }
     }
   }, [lastMessage, dispatch, classId]);
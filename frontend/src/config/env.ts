@@ -38,10 +38,12 @@ export const config = {
     baseUrl: env.VITE_API_BASE_URL || 'http://localhost:3000/api/v1',
     wsBaseUrl: getWebSocketUrl(env.VITE_API_BASE_URL || 'http://localhost:3000/api/v1'),
     
-    // WebSocket URL builder
-    getWebSocketUrl: (classId: string) => {
+    // WebSocket URL builder; browsers cannot set headers on WebSocket upgrades,
+    // so the teacher's access token goes in the query string
+    getWebSocketUrl: (classId: string, token?: string | null) => {
       const wsBase = getWebSocketUrl(env.VITE_API_BASE_URL || 'http://localhost:3000/api/v1');
-      return `${wsBase}/api/v1/classes/${classId}/ws`;
+      const query = token ? `?token=${encodeURIComponent(token)}` : '';
+      return `${wsBase}/api/v1/classes/${classId}/ws${query}`;
     },
   },
   
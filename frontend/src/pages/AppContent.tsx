@@ -3,7 +3,10 @@ import styled from 'styled-components'
 import ClassJoinModal from '../components/modal/ClassJoinModal'
 import ClassMgmtModal from '../components/modal/ClassMgmtModal'
 import { ClassList } from './ClassList'
+import { Login } from './Login'
 import { webSocketManager } from '../services/webSocketManager'
+import { authStore } from '../services/auth'
+import { apiService } from '../services/api'
 import { useClassInfo } from '../hooks/useClassInfo'
 
 interface AppContentProps {
@@ -15,6 +18,18 @@ export const AppContent: React.FC<AppContentProps> = () => {
   const [showRightModal, setShowRightModal] = useState(false);
   const [selectedClassId, setSelectedClassId] = useState<string | null>(null);
   const [directScanModes, setDirectScanModes] = useState<Record<string, boolean>>({});
+  const [session, setSession] = useState(() => authStore.getSession());
+
+  // Follow logins and logouts, including being logged out when the token is rejected
+  useEffect(() => authStore.subscribe((next) => {
+    setSession(next);
+    if (!next) {
+      webSocketManager.disconnect();
+      setSelectedClassId(null);
+      setShowLeftModal(false);
+      setShowRightModal(false);
+    }
+  }), []);
   
   // Use useClassInfo hook when a class is selected (inside Provider context)
   useClassInfo(selectedClassId || '');
@@ -85,8 +100,24 @@ export const AppContent: React.FC<AppContentProps> = () => {
     setShowRightModal(false);
   }, []);
 
+  const handleLogout = useCallback(() => {
+    apiService.logout();
+  }, []);
+
+  if (!session) {
+    return (
+      <AppContainer>
+        <Login />
+      </AppContainer>
+    );
+  }
+
   return (
     <AppContainer>
+      <TeacherBar>
+        <TeacherName>{session.teacher.name}</TeacherName>
+        <LogoutButton onClick={handleLogout}>Log out</LogoutButton>
+      </TeacherBar>
       <ClassListContainer>
         <ClassList 
           onSelectClass={handleSelectClass}
@@ -126,6 +157,31 @@ const AppContainer = styled.div`
   position: relative;
 `;
 
+const TeacherBar = styled.div`
+  display: flex;
+  justify-content: flex-end;
+  align-items: center;
+  gap: ${props => props.theme.spacing.md};
+  max-width: 1400px;
+  margin: 0 auto;
+  padding: ${props => props.theme.spacing.md} ${props => props.theme.spacing.lg} 0;
+`;
+
+const TeacherName = styled.span`
+  color: ${props => props.theme.colors.white};
+  font-weight: ${props => props.theme.typography.weights.medium};
+`;
+
+const LogoutButton = styled.button`
+  background: ${props => props.theme.colors.white};
+  color: ${props => props.theme.colors.primary};
+  border: none;
+  border-radius: ${props => props.theme.borderRadius.md};
+  padding: ${props => props.theme.spacing.xs} ${props => props.theme.spacing.md};
+  font-weight: ${props => props.theme.typography.weights.medium};
+  cursor: pointer;
+`;
+
 const ClassListContainer = styled.div`
   width: 100%;
   max-width: 1400px;
@@ -0,0 +1,126 @@
+import React, { useState, useCallback } from 'react';
+import styled from 'styled-components';
+import { apiService } from '../services/api';
+import { config } from '../config/env';
+
+export const Login: React.FC = () => {
+  const [email, setEmail] = useState('');
+  const [password, setPassword] = useState('');
+  const [submitting, setSubmitting] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const handleSubmit = useCallback(async (event: React.FormEvent) => {
+    event.preventDefault();
+    try {
+      setSubmitting(true);
+      setError(null);
+      // Saving the session switches the app over to the class list
+      await apiService.login(email, password);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to log in');
+      setSubmitting(false);
+    }
+  }, [email, password]);
+
+  return (
+    <Container>
+      <Card onSubmit={handleSubmit}>
+        <Title>{config.app.name}</Title>
+        <Label htmlFor="login-email">Email</Label>
+        <Input
+          id="login-email"
+          type="email"
+          autoComplete="email"
+          value={email}
+          onChange={(e) => setEmail(e.target.value)}
+          required
+          autoFocus
+        />
+        <Label htmlFor="login-password">Password</Label>
+        <Input
+          id="login-password"
+          type="password"
+          autoComplete="current-password"
+          value={password}
+          onChange={(e) => setPassword(e.target.value)}
+          required
+        />
+        {error && <ErrorMessage role="alert">{error}</ErrorMessage>}
+        <SubmitButton type="submit" disabled={submitting}>
+          {submitting ? 'Logging in...' : 'Log in'}
+        </SubmitButton>
+      </Card>
+    </Container>
+  );
+};
+
+const Container = styled.div`
+  display: flex;
+  align-items: center;
+  justify-content: center;
+  min-height: 100vh;
+  padding: ${props => props.theme.spacing.lg};
+  box-sizing: border-box;
+`;
+
+const Card = styled.form`
+  display: flex;
+  flex-direction: column;
+  width: 100%;
+  max-width: 400px;
+  background: ${props => props.theme.colors.white};
+  border-radius: ${props => props.theme.borderRadius.lg};
+  padding: ${props => props.theme.spacing.xl};
+  box-shadow: ${props => props.theme.shadows.lg};
+  box-sizing: border-box;
+`;
+
+const Title = styled.h1`
+  color: ${props => props.theme.colors.primary};
+  font-size: ${props => props.theme.typography.sizes.h1};
+  font-weight: ${props => props.theme.typography.weights.bold};
+  margin: 0 0 ${props => props.theme.spacing.lg};
+  text-align: center;
+`;
+
+const Label = styled.label`
+  color: ${props => props.theme.colors.gray[700]};
+  font-size: ${props => props.theme.typography.sizes.button};
+  font-weight: ${props => props.theme.typography.weights.medium};
+  margin-bottom: ${props => props.theme.spacing.xs};
+`;
+
+const Input = styled.input`
+  font-size: ${props => props.theme.typography.sizes.body};
+  padding: ${props => props.theme.spacing.sm} ${props => props.theme.spacing.md};
+  border: 1px solid ${props => props.theme.colors.gray[300]};
+  border-radius: ${props => props.theme.borderRadius.md};
+  margin-bottom: ${props => props.theme.spacing.md};
+
+  &:focus {
+    outline: none;
+    border-color: ${props => props.theme.colors.primary};
+  }
+`;
+
+const ErrorMessage = styled.div`
+  color: ${props => props.theme.colors.danger};
+  font-size: ${props => props.theme.typography.sizes.button};
+  margin-bottom: ${props => props.theme.spacing.md};
+`;
+
+const SubmitButton = styled.button`
+  background: ${props => props.theme.colors.primary};
+  color: ${props => props.theme.colors.white};
+  border: none;
+  border-radius: ${props => props.theme.borderRadius.md};
+  padding: ${props => props.theme.spacing.sm} ${props => props.theme.spacing.md};
+  font-size: ${props => props.theme.typography.sizes.body};
+  font-weight: ${props => props.theme.typography.weights.medium};
+  cursor: pointer;
+
+  &:disabled {
+    background: ${props => props.theme.colors.neutral};
+    cursor: default;
+  }
+`;
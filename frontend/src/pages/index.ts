@@ -1,3 +1,4 @@
 export { Dashboard } from './Dashboard';
 export { ClassList } from './ClassList';
-export { AppContent } from './AppContent';
\ No newline at end of file
+export { AppContent } from './AppContent';
+export { Login } from './Login';
@@ -0,0 +1,42 @@
+import { describe, it, expect, beforeEach, vi } from 'vitest';
+import { authStore } from '../auth';
+import type { AuthData } from '../../types/api';
+
+const session = (expiresInSeconds: number): AuthData => ({
+  token: 'signed-token',
+  expiresAt: Math.floor(Date.now() / 1000) + expiresInSeconds,
+  teacher: { id: 1, email: 'teacher@example.com', name: 'Ms. Lee' },
+});
+
+describe('authStore', () => {
+  beforeEach(() => {
+    localStorage.clear();
+  });
+
+  it('should return the saved token', () => {
+    authStore.save(session(3600));
+
+    expect(authStore.getToken()).toBe('signed-token');
+    expect(authStore.getSession()?.teacher.name).toBe('Ms. Lee');
+  });
+
+  it('should drop an expired session', () => {
+    authStore.save(session(-1));
+
+    expect(authStore.getToken()).toBeNull();
+    expect(localStorage.length).toBe(0);
+  });
+
+  it('should notify subscribers on login and logout', () => {
+    const listener = vi.fn();
+    const unsubscribe = authStore.subscribe(listener);
+
+    authStore.save(session(3600));
+    authStore.clear();
+    unsubscribe();
+    authStore.save(session(3600));
+
+    expect(listener).toHaveBeenCalledTimes(2);
+    expect(listener).toHaveBeenLastCalledWith(null);
+  });
+});
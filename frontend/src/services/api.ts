@@ -1,6 +1,7 @@
-import type { QRCodeResponse, APIResponse } from '../types/api';
+import type { QRCodeResponse, APIResponse, AuthData } from '../types/api';
 import type { ClassResponse, ClassInfo } from '../types/class';
 import { config } from '../config/env';
+import { authStore } from './auth';
 
 // Simple cache for API responses to avoid duplicate requests under CPU throttling
 const responseCache = new Map<string, { data: any; timestamp: number }>();
@@ -49,13 +50,53 @@ const setCachedResponse = (key: string, data: any) => {
   responseCache.set(key, { data, timestamp: Date.now() });
 };
 
+// Fetch with the teacher's access token; a 401 means it was rejected, so the teacher is logged out
+const authorizedFetch = async (url: string): Promise<Response> => {
+  const token = authStore.getToken();
+  const response = await fetch(url, {
+    headers: token ? { Authorization: `Bearer ${token}` } : {},
+  });
+  if (response.status === 401) {
+    authStore.clear();
+  }
+  return response;
+};
+
+// Cached responses belong to the teacher who fetched them
+authStore.subscribe(() => {
+  responseCache.clear();
+});
+
 export const apiService = {
+  async login(email: string, password: string): Promise<APIResponse<AuthData>> {
+    const response = await fetch(`${config.api.baseUrl}/auth/login`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ email, password }),
+    });
+
+    const data: APIResponse<AuthData> = await response.json().catch(() => ({
+      success: false,
+      message: response.statusText,
+    }));
+    if (!response.ok || !data.data) {
+      throw new Error(data.message || `Failed to log in: ${response.statusText}`);
+    }
+
+    authStore.save(data.data);
+    return data;
+  },
+
+  logout() {
+    authStore.clear();
+  },
+
   async getClasses(): Promise<APIResponse<ClassInfo[]>> {
     const cacheKey = 'classes';
     const cached = getCachedResponse(cacheKey);
     if (cached) return cached;
 
-    const response = await fetch(`${config.api.baseUrl}/classes`);
+    const response = await authorizedFetch(`${config.api.baseUrl}/classes`);
     
     if (!response.ok) {
       throw new Error(`Failed to fetch classes: ${response.statusText}`);
@@ -71,7 +112,7 @@ export const apiService = {
     const cached = getCachedResponse(cacheKey);
     if (cached) return cached;
 
-    const response = await fetch(`${config.api.baseUrl}/classes/${classId}`);
+    const response = await authorizedFetch(`${config.api.baseUrl}/classes/${classId}`);
     
     if (!response.ok) {
       throw new Error(`Failed to fetch class info: ${response.statusText}`);
@@ -88,7 +129,7 @@ export const apiService = {
     if (cached) return cached;
 
     const queryParam = isDirectMode ? '?mode=direct' : '';
-    const response = await fetch(`${config.api.baseUrl}/classes/${classId}/qr${queryParam}`);
+    const response = await authorizedFetch(`${config.api.baseUrl}/classes/${classId}/qr${queryParam}`);
     
     if (!response.ok) {
       throw new Error(`Failed to fetch QR code: ${response.statusText}`);
@@ -0,0 +1,61 @@
+/**
+ * Teacher session storage
+ * Keeps the access token returned at login in localStorage so the dashboard stays
+ * signed in across reloads, and tells subscribers when the teacher logs in or out.
+ */
+
+import type { AuthData } from '../types/api';
+
+const STORAGE_KEY = 'classswift.teacherSession';
+
+type AuthListener = (session: AuthData | null) => void;
+
+const listeners = new Set<AuthListener>();
+
+const notify = (session: AuthData | null) => {
+  listeners.forEach(listener => listener(session));
+};
+
+const isExpired = (session: AuthData) => session.expiresAt * 1000 <= Date.now();
+
+export const authStore = {
+  // Returns the stored session, dropping it once the token has expired
+  getSession(): AuthData | null {
+    const stored = localStorage.getItem(STORAGE_KEY);
+    if (!stored) return null;
+
+    try {
+      const session: AuthData = JSON.parse(stored);
+      if (!session.token || isExpired(session)) {
+        localStorage.removeItem(STORAGE_KEY);
+        return null;
+      }
+      return session;
+    } catch {
+      localStorage.removeItem(STORAGE_KEY);
+      return null;
+    }
+  },
+
+  getToken(): string | null {
+    return authStore.getSession()?.token ?? null;
+  },
+
+  save(session: AuthData) {
+    localStorage.setItem(STORAGE_KEY, JSON.stringify(session));
+    notify(session);
+  },
+
+  clear() {
+    if (localStorage.getItem(STORAGE_KEY) === null) return;
+    localStorage.removeItem(STORAGE_KEY);
+    notify(null);
+  },
+
+  subscribe(listener: AuthListener): () => void {
+    listeners.add(listener);
+    return () => {
+      listeners.delete(listener);
+    };
+  },
+};
@@ -4,10 +4,14 @@
  */
 
 import { config } from '../config/env';
+import { authStore } from './auth';
 
+// Message envelope of the backend WebSocket protocol; see GET /api/v1/ws/schema
 export interface WebSocketMessage {
+  version: number;
   type: string;
   classId: string;
+  seq?: number;
   data: any;
   timestamp: string;
 }
@@ -109,9 +113,9 @@ class WebSocketManager {
       this.isConnecting = true;
       this.connectionId++; // Increment connection ID for this attempt
       
-      const wsUrl = config.api.getWebSocketUrl(classId);
+      const wsUrl = config.api.getWebSocketUrl(classId, authStore.getToken());
       const currentConnectionId = this.connectionId;
-      console.log(`🔌 Creating WebSocket connection to: ${wsUrl} (ID: ${currentConnectionId})`);
+      console.log(`🔌 Creating WebSocket connection to: ${config.api.getWebSocketUrl(classId)} (ID: ${currentConnectionId})`);
       
       try {
         this.ws = new WebSocket(wsUrl);
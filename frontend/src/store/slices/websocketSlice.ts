@@ -1,9 +1,12 @@
 import { createSlice } from '@reduxjs/toolkit';
 import type { PayloadAction } from '@reduxjs/toolkit';
 
+// Message envelope of the backend WebSocket protocol; see GET /api/v1/ws/schema
 export interface WebSocketMessage {
+  version: number;
   type: string;
   classId: string;
+  seq?: number;
   data: any;
   timestamp: string;
 }
@@ -13,4 +13,16 @@ export interface QRCodeData {
 
 export interface QRCodeResponse extends APIResponse<QRCodeData> {
   data: QRCodeData;
-}
\ No newline at end of file
+}
+export interface Teacher {
+  id: number;
+  email: string;
+  name: string;
+}
+
+// Teacher access token returned by POST /auth/login; expiresAt is in Unix seconds
+export interface AuthData {
+  token: string;
+  expiresAt: number;
+  teacher: Teacher;
+}